@@ -0,0 +1,153 @@
+// Package archive reads and writes the account archives of the backup and restore commands.
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/quail-ink/quail-cli/client"
+)
+
+// Version is the version of the archive layout written by Backup.Write.
+// Read refuses archives with a newer version.
+const Version = 1
+
+type (
+	Manifest struct {
+		Version   int       `json:"version"`
+		CreatedAt time.Time `json:"created_at"`
+		APIBase   string    `json:"api_base"`
+		UserID    uint64    `json:"user_id"`
+		Lists     []string  `json:"lists"`
+		// Images maps the original image URL to its path inside the archive.
+		Images map[string]string `json:"images"`
+	}
+
+	List struct {
+		List  client.List   `json:"list"`
+		Posts []client.Post `json:"posts"`
+	}
+
+	// Backup is the in-memory form of an account archive:
+	//
+	//	manifest.json
+	//	user.json
+	//	lists/<list-slug>.json
+	//	images/<name>
+	Backup struct {
+		Manifest Manifest
+		User     *client.UserResponse
+		Lists    []List
+		Images   map[string][]byte
+	}
+)
+
+// Write writes the backup as a gzipped tarball.
+func (b *Backup) Write(w io.Writer) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	writeFile := func(name string, data []byte) error {
+		hdr := &tar.Header{
+			Name:    name,
+			Mode:    0644,
+			Size:    int64(len(data)),
+			ModTime: b.Manifest.CreatedAt,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	writeJSON := func(name string, v any) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("could not marshal %s: %w", name, err)
+		}
+		return writeFile(name, data)
+	}
+
+	if err := writeJSON("manifest.json", b.Manifest); err != nil {
+		return err
+	}
+	if err := writeJSON("user.json", b.User); err != nil {
+		return err
+	}
+	for _, list := range b.Lists {
+		if err := writeJSON(path.Join("lists", list.List.Slug+".json"), list); err != nil {
+			return err
+		}
+	}
+	for name, data := range b.Images {
+		if err := writeFile(name, data); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// Read reads an archive written by Backup.Write.
+func Read(r io.Reader) (*Backup, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("could not open archive: %w", err)
+	}
+	defer gr.Close()
+
+	b := &Backup{Images: map[string][]byte{}}
+	hasManifest := false
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read archive: %w", err)
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("could not read %s: %w", hdr.Name, err)
+		}
+
+		switch {
+		case hdr.Name == "manifest.json":
+			if err := json.Unmarshal(data, &b.Manifest); err != nil {
+				return nil, fmt.Errorf("could not parse manifest: %w", err)
+			}
+			hasManifest = true
+		case hdr.Name == "user.json":
+			b.User = &client.UserResponse{}
+			if err := json.Unmarshal(data, b.User); err != nil {
+				return nil, fmt.Errorf("could not parse user: %w", err)
+			}
+		case strings.HasPrefix(hdr.Name, "lists/"):
+			list := List{}
+			if err := json.Unmarshal(data, &list); err != nil {
+				return nil, fmt.Errorf("could not parse %s: %w", hdr.Name, err)
+			}
+			b.Lists = append(b.Lists, list)
+		case strings.HasPrefix(hdr.Name, "images/"):
+			b.Images[hdr.Name] = data
+		}
+	}
+
+	if !hasManifest {
+		return nil, fmt.Errorf("archive has no manifest.json")
+	}
+	if b.Manifest.Version > Version {
+		return nil, fmt.Errorf("archive version %d is newer than supported version %d", b.Manifest.Version, Version)
+	}
+	return b, nil
+}
@@ -36,9 +36,40 @@ func New(accessToken, apiBase string) *Client {
 	}
 }
 
-func (c *Client) GetList(listID string) (any, error) {
-	url := fmt.Sprintf("%s/lists/%s", c.APIBase, listID)
-	return c.sendRequest("GET", url, nil)
+func (c *Client) GetList(listIDOrSlug string) (*ListResponse, error) {
+	resp, err := c.sendRequest("GET", fmt.Sprintf("%s/lists/%s", c.APIBase, listIDOrSlug), nil)
+	if err != nil {
+		return nil, err
+	}
+	lr := &ListResponse{}
+	if err := json.Unmarshal(resp, lr); err != nil {
+		return nil, err
+	}
+	return lr, nil
+}
+
+func (c *Client) GetMyLists() (*ListsResponse, error) {
+	resp, err := c.sendRequest("GET", fmt.Sprintf("%s/users/me/lists", c.APIBase), nil)
+	if err != nil {
+		return nil, err
+	}
+	lr := &ListsResponse{}
+	if err := json.Unmarshal(resp, lr); err != nil {
+		return nil, err
+	}
+	return lr, nil
+}
+
+func (c *Client) CreateList(payload map[string]any) (*ListResponse, error) {
+	resp, err := c.sendRequest("POST", fmt.Sprintf("%s/lists", c.APIBase), payload)
+	if err != nil {
+		return nil, err
+	}
+	lr := &ListResponse{}
+	if err := json.Unmarshal(resp, lr); err != nil {
+		return nil, err
+	}
+	return lr, nil
 }
 
 func (c *Client) GetMe() (*UserResponse, error) {
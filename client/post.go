@@ -17,6 +17,18 @@ func (c *Client) GetPost(listIDOrSlug string, slug string) (*PostResponse, error
 	return pr, nil
 }
 
+func (c *Client) GetPosts(listIDOrSlug string, offset, limit int) (*PostsResponse, error) {
+	resp, err := c.sendRequest("GET", fmt.Sprintf("%s/lists/%s/posts?offset=%d&limit=%d", c.APIBase, listIDOrSlug, offset, limit), nil)
+	if err != nil {
+		return nil, err
+	}
+	pr := &PostsResponse{}
+	if err := json.Unmarshal(resp, pr); err != nil {
+		return nil, err
+	}
+	return pr, nil
+}
+
 func (c *Client) CreatePost(listIDOrSlug string, payload map[string]any) (*PostResponse, error) {
 	resp, err := c.sendRequest("POST", fmt.Sprintf("%s/lists/%s/posts", c.APIBase, listIDOrSlug), payload)
 	if err != nil {
@@ -29,20 +29,58 @@ type (
 
 type (
 	PostResponse struct {
+		Data Post `json:"data"`
+	}
+	PostsResponse struct {
 		Data struct {
-			ID               uint64    `json:"id"`
-			Slug             string    `json:"slug"`
-			CoverImageURL    string    `json:"cover_image_url"`
-			Title            string    `json:"title"`
-			Summary          string    `json:"summary"`
-			Content          string    `json:"content"`
-			PaidContent      string    `json:"paid_content"`
-			UserID           uint64    `json:"user_id"`
-			ListID           uint64    `json:"list_id"`
-			Tags             string    `json:"tags"`
-			Theme            string    `json:"theme"`
-			PublishedAt      time.Time `json:"published_at"`
-			FirstPublishedAt time.Time `json:"first_published_at"`
+			Items []Post `json:"items"`
+			Total int    `json:"total"`
 		} `json:"data"`
 	}
+	Post struct {
+		ID               uint64    `json:"id"`
+		Slug             string    `json:"slug"`
+		CoverImageURL    string    `json:"cover_image_url"`
+		Title            string    `json:"title"`
+		Summary          string    `json:"summary"`
+		Content          string    `json:"content"`
+		PaidContent      string    `json:"paid_content"`
+		UserID           uint64    `json:"user_id"`
+		ListID           uint64    `json:"list_id"`
+		Tags             string    `json:"tags"`
+		Theme            string    `json:"theme"`
+		PublishedAt      time.Time `json:"published_at"`
+		FirstPublishedAt time.Time `json:"first_published_at"`
+	}
+)
+
+type (
+	ListResponse struct {
+		Data List `json:"data"`
+	}
+	ListsResponse struct {
+		Data []List `json:"data"`
+	}
+	List struct {
+		ID          uint64 `json:"id"`
+		Slug        string `json:"slug"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		UserID      uint64 `json:"user_id"`
+		Lang        string `json:"lang"`
+		CreatedAt   string `json:"created_at"`
+	}
+)
+
+type (
+	AttachmentResponse struct {
+		Data Attachment `json:"data"`
+	}
+	Attachment struct {
+		ID          uint64 `json:"id"`
+		URL         string `json:"url"`
+		Filename    string `json:"filename"`
+		ContentType string `json:"content_type"`
+		Size        int64  `json:"size"`
+	}
 )
@@ -0,0 +1,176 @@
+package backup
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path"
+	"time"
+
+	"github.com/quail-ink/quail-cli/archive"
+	"github.com/quail-ink/quail-cli/client"
+	"github.com/quail-ink/quail-cli/cmd/common"
+	"github.com/quail-ink/quail-cli/util"
+	"github.com/spf13/cobra"
+)
+
+const postsPageSize = 50
+
+var (
+	output   string
+	noImages bool
+)
+
+func fetchPosts(cl *client.Client, listSlug string) ([]client.Post, error) {
+	posts := []client.Post{}
+	for offset := 0; ; offset += postsPageSize {
+		result, err := cl.GetPosts(listSlug, offset, postsPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range result.Data.Items {
+			// the post list may omit the content, so fetch every post in full
+			full, err := cl.GetPost(listSlug, item.Slug)
+			if err != nil {
+				return nil, fmt.Errorf("could not get post %s: %w", item.Slug, err)
+			}
+			posts = append(posts, full.Data)
+		}
+		if len(result.Data.Items) < postsPageSize {
+			break
+		}
+	}
+	return posts, nil
+}
+
+func fetchImage(url string) ([]byte, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func imageName(url string) string {
+	sum := sha1.Sum([]byte(url))
+	return path.Join("images", hex.EncodeToString(sum[:])+path.Ext(path.Base(url)))
+}
+
+func backup(cl *client.Client, apiBase string) (*archive.Backup, error) {
+	me, err := cl.GetMe()
+	if err != nil {
+		return nil, fmt.Errorf("could not get user: %w", err)
+	}
+	lists, err := cl.GetMyLists()
+	if err != nil {
+		return nil, fmt.Errorf("could not get lists: %w", err)
+	}
+
+	b := &archive.Backup{
+		Manifest: archive.Manifest{
+			Version:   archive.Version,
+			CreatedAt: time.Now(),
+			APIBase:   apiBase,
+			UserID:    me.Data.ID,
+			Images:    map[string]string{},
+		},
+		User:   me,
+		Images: map[string][]byte{},
+	}
+
+	imageURLs := []string{}
+	if me.Data.AvatarImageURL != "" {
+		imageURLs = append(imageURLs, me.Data.AvatarImageURL)
+	}
+
+	for _, item := range lists.Data {
+		// GetList returns the full list details
+		list, err := cl.GetList(item.Slug)
+		if err != nil {
+			return nil, fmt.Errorf("could not get list %s: %w", item.Slug, err)
+		}
+		posts, err := fetchPosts(cl, item.Slug)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(os.Stderr, "Backed up list %s: %d posts\n", item.Slug, len(posts))
+
+		for _, post := range posts {
+			if post.CoverImageURL != "" {
+				imageURLs = append(imageURLs, post.CoverImageURL)
+			}
+			imageURLs = append(imageURLs, util.ExtractImageURLs(post.Content)...)
+			imageURLs = append(imageURLs, util.ExtractImageURLs(post.PaidContent)...)
+		}
+
+		b.Manifest.Lists = append(b.Manifest.Lists, list.Data.Slug)
+		b.Lists = append(b.Lists, archive.List{List: list.Data, Posts: posts})
+	}
+
+	if noImages {
+		return b, nil
+	}
+	for _, url := range imageURLs {
+		if _, ok := b.Manifest.Images[url]; ok {
+			continue
+		}
+		data, err := fetchImage(url)
+		if err != nil {
+			slog.Warn("failed to download image", "url", url, "error", err)
+			continue
+		}
+		name := imageName(url)
+		b.Manifest.Images[url] = name
+		b.Images[name] = data
+	}
+
+	return b, nil
+}
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Back up the profile, lists, posts and images of the current user",
+		Run: func(cmd *cobra.Command, args []string) {
+			cl := cmd.Context().Value(common.CTX_CLIENT{}).(*client.Client)
+			apiBase := cmd.Context().Value(common.CTX_API_BASE{}).(string)
+
+			if output == "" {
+				output = fmt.Sprintf("quail-backup-%s.tar.gz", time.Now().Format("20060102-150405"))
+			}
+
+			b, err := backup(cl, apiBase)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+
+			file, err := os.Create(output)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			defer file.Close()
+
+			if err := b.Write(file); err != nil {
+				fmt.Println(err)
+				return
+			}
+
+			fmt.Printf("Backup saved to %s\n", output)
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Archive path (default is quail-backup-<time>.tar.gz)")
+	cmd.Flags().BoolVar(&noImages, "no-images", false, "Do not download referenced images")
+
+	return cmd
+}
@@ -1,9 +1,12 @@
 package common
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/quail-ink/quail-cli/core"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -44,3 +47,82 @@ func ConfigViper(cfgFile string) string {
 
 	return cfgFile
 }
+
+// FrontMatterMapping returns the `post.frontmatter_mapping` for a Markdown file,
+// merged with the mapping of the most specific entry of `post.directories`
+// containing the file. Relative directory paths are relative to the config file:
+//
+//	post:
+//	  directories:
+//	    - path: content/notes
+//	      frontmatter_mapping:
+//	        summary: description
+func FrontMatterMapping(filename string) (core.FrontMatterMapping, error) {
+	mapping, err := core.ParseFrontMatterMapping(viper.Get("post.frontmatter_mapping"))
+	if err != nil {
+		return nil, err
+	}
+
+	directories, ok := viper.Get("post.directories").([]any)
+	if !ok {
+		return mapping, nil
+	}
+
+	if filename == "" || filename == "-" {
+		filename = "."
+	}
+	absFile, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, err
+	}
+	configDir := "."
+	if configFile := viper.ConfigFileUsed(); configFile != "" {
+		configDir = filepath.Dir(configFile)
+	}
+
+	var override core.FrontMatterMapping
+	matched := ""
+	for _, item := range directories {
+		directory := viper.New()
+		if err := directory.MergeConfigMap(toStringMap(item)); err != nil {
+			return nil, fmt.Errorf("invalid post.directories entry: %w", err)
+		}
+		path := directory.GetString("path")
+		if path == "" {
+			return nil, fmt.Errorf("invalid post.directories entry: path is required")
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(configDir, path)
+		}
+		dir, err := filepath.Abs(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid post.directories entry for %s: %w", path, err)
+		}
+		if absFile != dir && !strings.HasPrefix(absFile, dir+string(filepath.Separator)) {
+			continue
+		}
+		if len(dir) <= len(matched) {
+			continue
+		}
+		if override, err = core.ParseFrontMatterMapping(directory.Get("frontmatter_mapping")); err != nil {
+			return nil, fmt.Errorf("invalid post.directories entry for %s: %w", dir, err)
+		}
+		matched = dir
+	}
+
+	return mapping.Merge(override), nil
+}
+
+func toStringMap(value any) map[string]any {
+	switch v := value.(type) {
+	case map[string]any:
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(key)] = item
+		}
+		return m
+	}
+	return nil
+}
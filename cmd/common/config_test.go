@@ -0,0 +1,70 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestFrontMatterMappingDirectories(t *testing.T) {
+	root := t.TempDir()
+	configFile := filepath.Join(root, "config.yaml")
+	config := `post:
+  frontmatter_mapping:
+    summary: description
+  directories:
+    - path: content
+      frontmatter_mapping:
+        title: headline
+    - path: content/notes
+      frontmatter_mapping:
+        summary: abstract
+`
+	if err := os.WriteFile(configFile, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer viper.Reset()
+	viper.SetConfigFile(configFile)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the directories are relative to the config file, not the working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		file    string
+		title   string
+		summary string
+	}{
+		{filepath.Join(root, "post.md"), "title", "description"},
+		{filepath.Join(root, "content", "post.md"), "headline", "description"},
+		// only the most specific directory applies
+		{filepath.Join(root, "content", "notes", "post.md"), "title", "abstract"},
+		{filepath.Join(root, "contents", "post.md"), "title", "description"},
+		{"content/post.md", "title", "description"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			mapping, err := FrontMatterMapping(tt.file)
+			if err != nil {
+				t.Fatalf("FrontMatterMapping() error = %v", err)
+			}
+			if got := mapping.Name("title"); got != tt.title {
+				t.Errorf("title is mapped to %q, want %q", got, tt.title)
+			}
+			if got := mapping.Name("summary"); got != tt.summary {
+				t.Errorf("summary is mapped to %q, want %q", got, tt.summary)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/quail-ink/quail-cli/transform"
+	"github.com/spf13/viper"
+)
+
+// ProjectFile is the configuration of a project, such as a blog repository. It is
+// looked up in the directory of a post and its parents.
+const ProjectFile = ".quail.yaml"
+
+type Project struct {
+	// Root is the directory of the project file, or the current directory if there is none.
+	Root string
+	// Transformers run after the built-in transformers, in order.
+	Transformers []transform.StepConfig `mapstructure:"transformers"`
+	// DisableTransformers turns off built-in transformers by name.
+	DisableTransformers []string `mapstructure:"disable_transformers"`
+}
+
+// LoadProject loads the project file of a post, "" or "-" for the current directory.
+func LoadProject(filename string) (*Project, error) {
+	dir := "."
+	if filename != "" && filename != "-" {
+		dir = filepath.Dir(filename)
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		path := filepath.Join(dir, ProjectFile)
+		if _, err := os.Stat(path); err == nil {
+			v := viper.New()
+			v.SetConfigFile(path)
+			if err := v.ReadInConfig(); err != nil {
+				return nil, fmt.Errorf("could not read %s: %w", path, err)
+			}
+			project := &Project{Root: dir}
+			if err := v.Unmarshal(project); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", path, err)
+			}
+			return project, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return &Project{Root: cwd}, nil
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,209 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/quail-ink/quail-cli/client"
+	"github.com/quail-ink/quail-cli/cmd/common"
+	"github.com/quail-ink/quail-cli/cmd/post"
+	"github.com/quail-ink/quail-cli/core"
+	"github.com/quail-ink/quail-cli/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var (
+	listSlug  string
+	doPublish bool
+)
+
+// importedState records the GUIDs of feed entries that have been imported, per list,
+// so that running the import again only sends new entries.
+type importedState map[string]map[string]string
+
+func statePath(cfgFile string) string {
+	return filepath.Join(filepath.Dir(cfgFile), "imported.yaml")
+}
+
+func loadState(path string) (importedState, error) {
+	state := importedState{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read import state: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("could not parse import state: %w", err)
+	}
+	return state, nil
+}
+
+func saveState(path string, state importedState) error {
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("could not marshal import state: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func openFeed(source string) (io.ReadCloser, error) {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		resp, err := http.Get(source)
+		if err != nil {
+			return nil, fmt.Errorf("could not fetch feed: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("could not fetch feed: %s", resp.Status)
+		}
+		return resp.Body, nil
+	}
+	file, err := os.Open(source)
+	if err != nil {
+		return nil, fmt.Errorf("could not open file: %w", err)
+	}
+	return file, nil
+}
+
+func entryToPost(entry util.FeedEntry) (*core.QuailPostFrontMatter, string, error) {
+	content, err := util.HTMLToMarkdown(entry.Content)
+	if err != nil {
+		return nil, "", err
+	}
+	warn := func(msg string) {
+		fmt.Fprintf(os.Stderr, "warning: %s: %s\n", entry.GUID, msg)
+	}
+	content = util.ConvertShortcodes(content, nil, warn)
+	if viper.GetBool("post.mdx") {
+		content = util.ConvertMDX(content, false, warn)
+	}
+	if content, err = post.ConvertCallouts(content); err != nil {
+		return nil, "", err
+	}
+
+	summary := ""
+	if entry.Summary != "" {
+		summary, err = util.HTMLToMarkdown(entry.Summary)
+		if err != nil {
+			return nil, "", err
+		}
+		summary = strings.TrimSpace(summary)
+	}
+
+	slug := util.SlugFromURL(entry.Link)
+	if slug == "" {
+		slug = util.Slugify(entry.Title)
+	}
+
+	frontMatter := &core.QuailPostFrontMatter{
+		Slug:     slug,
+		Title:    entry.Title,
+		Summary:  summary,
+		Tags:     strings.Join(entry.Tags, ","),
+		Datetime: entry.PublishedAt,
+	}
+	return frontMatter, content, nil
+}
+
+func importFeed(cl *client.Client, source, cfgFile, format string) error {
+	reader, err := openFeed(source)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	entries, err := util.ParseFeed(reader)
+	if err != nil {
+		return err
+	}
+
+	path := statePath(cfgFile)
+	state, err := loadState(path)
+	if err != nil {
+		return err
+	}
+	if state[listSlug] == nil {
+		state[listSlug] = map[string]string{}
+	}
+
+	for _, entry := range entries {
+		if entry.GUID == "" {
+			slog.Warn("skip feed entry without guid or link", "title", entry.Title)
+			continue
+		}
+		if slug, ok := state[listSlug][entry.GUID]; ok {
+			if format != common.FORMAT_JSON {
+				fmt.Printf("Skip %s: already imported as %s\n", entry.GUID, slug)
+			}
+			continue
+		}
+
+		frontMatter, content, err := entryToPost(entry)
+		if err != nil {
+			return fmt.Errorf("could not convert %s: %w", entry.GUID, err)
+		}
+
+		result, err := post.Upsert(cl, listSlug, frontMatter, content, post.UpsertOptions{
+			Publish:     doPublish,
+			SummaryMode: util.SummaryModeAuto,
+		})
+		if err != nil {
+			return fmt.Errorf("could not import %s: %w", entry.GUID, err)
+		}
+
+		state[listSlug][entry.GUID] = result.Data.Slug
+		if err := saveState(path, state); err != nil {
+			return err
+		}
+
+		if format == common.FORMAT_JSON {
+			client.PrettyPrintJSON(result)
+		} else {
+			client.PrettyPrintPost(result)
+		}
+	}
+
+	return nil
+}
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import feed <file-or-url>",
+		Short: "Import posts from other sources",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) < 2 || listSlug == "" {
+				cmd.Help()
+				return
+			}
+
+			format := cmd.Context().Value(common.CTX_FORMAT{}).(string)
+			cl := cmd.Context().Value(common.CTX_CLIENT{}).(*client.Client)
+			cfgFile := cmd.Context().Value(common.CTX_CONFIG_FILE{}).(string)
+			cfgFile = common.ConfigViper(cfgFile)
+
+			switch args[0] {
+			case "feed":
+				if err := importFeed(cl, args[1], cfgFile, format); err != nil {
+					fmt.Println(err)
+					return
+				}
+			default:
+				cmd.Help()
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&listSlug, "list", "l", "", "List slug")
+	cmd.Flags().BoolVar(&doPublish, "publish", false, "Publish the imported posts")
+
+	return cmd
+}
@@ -0,0 +1,58 @@
+package post
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/quail-ink/quail-cli/util"
+	"github.com/spf13/viper"
+)
+
+// calloutStyles returns the default callout styles with the ones of `post.callouts.types`.
+// A configured type only needs the fields it changes.
+func calloutStyles() (map[string]util.CalloutStyle, error) {
+	configured := map[string]util.CalloutStyle{}
+	if err := viper.UnmarshalKey("post.callouts.types", &configured); err != nil {
+		return nil, fmt.Errorf("invalid post.callouts.types config: %w", err)
+	}
+	styles := map[string]util.CalloutStyle{}
+	for kind, style := range util.DefaultCalloutStyles {
+		styles[kind] = style
+	}
+	for kind, style := range configured {
+		kind = strings.ToLower(kind)
+		base, ok := styles[kind]
+		if !ok {
+			base = util.CalloutStyle{Alert: "NOTE"}
+		}
+		if style.Icon != "" {
+			base.Icon = style.Icon
+		}
+		if style.Label != "" {
+			base.Label = style.Label
+		}
+		if style.Alert != "" {
+			base.Alert = strings.ToUpper(style.Alert)
+		}
+		styles[kind] = base
+	}
+	return styles, nil
+}
+
+// ConvertCallouts renders admonitions, GitHub alerts and Obsidian callouts in the
+// format of `post.callouts.format`.
+func ConvertCallouts(content string) (string, error) {
+	styles, err := calloutStyles()
+	if err != nil {
+		return "", err
+	}
+	format := viper.GetString("post.callouts.format")
+	switch format {
+	case "":
+		format = util.CalloutFormatQuote
+	case util.CalloutFormatQuote, util.CalloutFormatGitHub:
+	default:
+		return "", fmt.Errorf("unknown callout format: %s", format)
+	}
+	return util.ConvertCallouts(util.ConvertAdmonitions(content), styles, format), nil
+}
@@ -0,0 +1,155 @@
+package post
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/quail-ink/quail-cli/client"
+	"github.com/quail-ink/quail-cli/cmd/common"
+	"github.com/quail-ink/quail-cli/core"
+	"github.com/quail-ink/quail-cli/lint"
+	"github.com/quail-ink/quail-cli/util"
+	"github.com/spf13/viper"
+)
+
+// postsPageSize is the number of posts fetched per request when listing posts.
+const postsPageSize = 50
+
+// siteURL returns the public URL of the Quail site posts are published on, from
+// `post.site_url`.
+func siteURL() string {
+	if url := viper.GetString("post.site_url"); url != "" {
+		return url
+	}
+	return core.DefaultSiteURL
+}
+
+// postStatuses looks up posts on Quail. The posts of a list are fetched once,
+// however many links point to them.
+type postStatuses struct {
+	cl    *client.Client
+	lists map[string]map[string]lint.PostStatus
+	errs  map[string]error
+}
+
+func (s *postStatuses) lookup(list, slug string) (lint.PostStatus, error) {
+	if err, ok := s.errs[list]; ok {
+		return lint.PostMissing, err
+	}
+	posts, ok := s.lists[list]
+	if !ok {
+		posts = map[string]lint.PostStatus{}
+		for offset := 0; ; offset += postsPageSize {
+			result, err := s.cl.GetPosts(list, offset, postsPageSize)
+			if err != nil {
+				s.errs[list] = fmt.Errorf("could not get posts of %s: %w", list, err)
+				return lint.PostMissing, s.errs[list]
+			}
+			for _, post := range result.Data.Items {
+				status := lint.PostPublished
+				if post.PublishedAt.IsZero() {
+					status = lint.PostUnpublished
+				}
+				posts[post.Slug] = status
+			}
+			if len(result.Data.Items) < postsPageSize {
+				break
+			}
+		}
+		s.lists[list] = posts
+	}
+	if status, ok := posts[slug]; ok {
+		return status, nil
+	}
+	return lint.PostMissing, nil
+}
+
+// newLinkChecker returns a link checker that looks up linked posts on Quail if lookup is set.
+func newLinkChecker(cl *client.Client, lookup bool) *lint.LinkChecker {
+	// the time zone is checked when the command starts
+	loc, _ := location()
+	checker := &lint.LinkChecker{
+		BaseURL:    siteURL(),
+		MappingFor: common.FrontMatterMapping,
+		Location:   loc,
+		SlugFor: func(title string) string {
+			return util.SlugifyTitle(title, slugMaxLength())
+		},
+	}
+	if lookup {
+		statuses := &postStatuses{cl: cl, lists: map[string]map[string]lint.PostStatus{}, errs: map[string]error{}}
+		checker.LookupPost = statuses.lookup
+	}
+	return checker
+}
+
+// rewriteLocalLinks replaces relative links to other Markdown files with the Quail
+// URLs of the posts they are published as, and warns about posts that are not
+// published yet. Links that cannot be resolved are kept.
+func rewriteLocalLinks(cl *client.Client, file, list, content string) string {
+	checker := newLinkChecker(cl, true)
+	return util.RewriteLinks(content, func(link util.Link) (string, bool) {
+		if link.Image || !link.IsRelative() {
+			return "", false
+		}
+		res := checker.CheckLink(file, list, link)
+		if res.Status != lint.LinkOK {
+			fmt.Fprintf(os.Stderr, "warning: %s: %s\n", link.Destination, res.Message)
+		}
+		if res.Target == "" {
+			return "", false
+		}
+
+		targetList, slug, _ := strings.Cut(res.Target, "/")
+		dest := core.PostURL(siteURL(), targetList, slug)
+		if _, fragment, ok := strings.Cut(link.Destination, "#"); ok {
+			dest += "#" + fragment
+		}
+		return dest, true
+	})
+}
+
+// checkLinks checks the links of the files and prints the results per file. It
+// exits with status 1 if any link has a problem.
+func checkLinks(cl *client.Client, files []string, format string) error {
+	config := lint.LinkCheckConfig{}
+	if err := viper.UnmarshalKey("post.links", &config); err != nil {
+		return fmt.Errorf("invalid post.links config: %w", err)
+	}
+
+	checker := newLinkChecker(cl, remote)
+	checker.Config = config
+	checker.List = listSlug
+
+	results := []lint.FileLinks{}
+	failed := false
+	for _, file := range files {
+		result := checker.Check(file)
+		if result.Error != "" || len(result.Problems()) > 0 {
+			failed = true
+		}
+		results = append(results, result)
+	}
+
+	if format == common.FORMAT_JSON {
+		client.PrettyPrintJSON(results)
+	} else {
+		for _, result := range results {
+			if result.Error != "" {
+				fmt.Printf("%s: %s\n", result.File, result.Error)
+				continue
+			}
+			problems := result.Problems()
+			fmt.Printf("%s: %d links, %d problems\n", result.File, len(result.Links), len(problems))
+			for _, link := range problems {
+				fmt.Printf("  %d:%d: %s: %s (%s)\n", link.Line, link.Column, link.Status, link.Message, link.Destination)
+			}
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+	return nil
+}
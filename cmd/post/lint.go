@@ -0,0 +1,51 @@
+package post
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/quail-ink/quail-cli/client"
+	"github.com/quail-ink/quail-cli/cmd/common"
+	"github.com/quail-ink/quail-cli/lint"
+	"github.com/spf13/viper"
+)
+
+// lintPosts checks the files and prints the problems. It exits with status 1 if
+// there are errors, so it can be used in CI.
+func lintPosts(files []string, format string) error {
+	config := lint.DefaultConfig()
+	if err := viper.UnmarshalKey("post.lint", &config); err != nil {
+		return fmt.Errorf("invalid post.lint config: %w", err)
+	}
+	loc, err := location()
+	if err != nil {
+		return err
+	}
+	config.Location = loc
+	linter, err := lint.New(config)
+	if err != nil {
+		return err
+	}
+
+	problems := []lint.Problem{}
+	for _, file := range files {
+		mapping, err := common.FrontMatterMapping(file)
+		if err != nil {
+			return err
+		}
+		problems = append(problems, linter.Lint(file, mapping)...)
+	}
+
+	if format == common.FORMAT_JSON {
+		client.PrettyPrintJSON(problems)
+	} else {
+		for _, problem := range problems {
+			fmt.Println(problem)
+		}
+	}
+
+	if lint.HasErrors(problems) {
+		os.Exit(1)
+	}
+	return nil
+}
@@ -3,63 +3,82 @@ package post
 import (
 	"fmt"
 	"log/slog"
-	"time"
+	"os"
 
 	"github.com/quail-ink/quail-cli/client"
 	"github.com/quail-ink/quail-cli/cmd/common"
+	"github.com/quail-ink/quail-cli/core"
 	"github.com/quail-ink/quail-cli/util"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var (
-	listSlug  string
-	postSlug  string
-	doPublish bool
+	listSlug    string
+	postSlug    string
+	doPublish   bool
+	output      string
+	summaryMode string
+	writeSlug   bool
+	writeBack   bool
+	isDraft     bool
+	doDeliver   bool
+	isPaid      bool
+	canonical   string
+	lang        string
+	timezone    string
+	remote      bool
+	vaultDir    string
+
+	normalizeCJK bool
+	noPager      bool
+
+	// frontmatter overrides
+	titleOverride   string
+	slugOverride    string
+	tagsOverride    string
+	summaryOverride string
+	coverOverride   string
 )
 
-func upsertPost(cl *client.Client, filepath string, frontMatterMapping map[string]string, format string) error {
-	if filepath == "" {
-		return fmt.Errorf("filepath is required")
-	}
-
-	frontMatter, content, err := util.ParseMarkdownWithFrontMatter(filepath, frontMatterMapping)
+// pullPost writes a post as a Markdown file, joining its paid content back
+// with the paywall marker.
+func pullPost(cl *client.Client, output string, frontMatterMapping core.FrontMatterMapping) error {
+	result, err := cl.GetPost(listSlug, postSlug)
 	if err != nil {
 		return err
 	}
-
-	var datetime *time.Time
-	if doPublish {
-		datetime = frontMatter.Datetime
-		if datetime == nil {
-			now := time.Now()
-			datetime = &now
-		}
+	if result.Data.ID == 0 {
+		return fmt.Errorf("post %s not found in list %s", postSlug, listSlug)
 	}
 
-	payload := map[string]any{
-		"slug":               frontMatter.Slug,
-		"cover_image_url":    frontMatter.CoverImageUrl,
-		"title":              frontMatter.Title,
-		"summary":            frontMatter.Summary,
-		"content":            content,
-		"datetime":           datetime,
-		"first_published_at": frontMatter.Datetime,
-		"tags":               frontMatter.Tags,
-		"theme":              frontMatter.Theme,
+	post := result.Data
+	frontMatter := &core.QuailPostFrontMatter{
+		Slug:          post.Slug,
+		CoverImageUrl: post.CoverImageURL,
+		Title:         post.Title,
+		Summary:       post.Summary,
+		Theme:         post.Theme,
+		Tags:          post.Tags,
+	}
+	if !post.FirstPublishedAt.IsZero() {
+		frontMatter.Datetime = &post.FirstPublishedAt
 	}
 
-	result, err := cl.CreatePost(listSlug, payload)
+	content := util.JoinPaidContent(post.Content, post.PaidContent, paywallMarker())
+	markdown, err := util.RenderMarkdownWithFrontMatter(frontMatter, content, frontMatterMapping)
 	if err != nil {
 		return err
 	}
 
-	if format == common.FORMAT_JSON {
-		client.PrettyPrintJSON(result)
-	} else {
-		client.PrettyPrintPost(result)
+	if output == "" {
+		fmt.Print(markdown)
+		return nil
 	}
-
+	if err := os.WriteFile(output, []byte(markdown), 0644); err != nil {
+		return fmt.Errorf("could not write file: %w", err)
+	}
+	fmt.Printf("Post saved to %s\n", output)
 	return nil
 }
 
@@ -82,7 +101,7 @@ func modPost(cmd *cobra.Command, cl *client.Client, op, format string) {
 
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "post upsert [filepath]\n\tpost <delete||publish|unpublish|deliver>",
+		Use:   "post upsert <filepath|-> [-l list] [--vault dir]\n\tpost lint <filepath...>\n\tpost check-links <filepath...> [-l list]\n\tpost stats <filepath|->\n\tpost preview <filepath|->\n\tpost pull -l list -p post [-o filepath]\n\tpost <delete||publish|unpublish|deliver>",
 		Short: "Manpulate posts",
 		Run: func(cmd *cobra.Command, args []string) {
 			if len(args) == 0 {
@@ -98,7 +117,20 @@ func NewCmd() *cobra.Command {
 				panic(err)
 			}
 
-			frontMatterMapping := viper.GetStringMapString("post.frontmatter_mapping")
+			filename := ""
+			if len(args) > 1 {
+				filename = args[1]
+			}
+			frontMatterMapping, err := common.FrontMatterMapping(filename)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+
+			if _, err := location(); err != nil {
+				fmt.Println(err)
+				return
+			}
 
 			action := args[0]
 			switch action {
@@ -109,7 +141,52 @@ func NewCmd() *cobra.Command {
 				}
 
 				filepath := args[1]
-				if err := upsertPost(cl, filepath, frontMatterMapping, format); err != nil {
+				if err := upsertPost(cmd, cl, filepath, frontMatterMapping, format); err != nil {
+					fmt.Println(err)
+					return
+				}
+			case "lint":
+				if len(args) < 2 {
+					cmd.Help()
+					return
+				}
+				if err := lintPosts(args[1:], format); err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+			case "preview":
+				if len(args) < 2 {
+					cmd.Help()
+					return
+				}
+				if err := previewPost(cl, args[1], frontMatterMapping); err != nil {
+					fmt.Println(err)
+					return
+				}
+			case "stats":
+				if len(args) < 2 {
+					cmd.Help()
+					return
+				}
+				if err := statsPost(args[1], frontMatterMapping, format); err != nil {
+					fmt.Println(err)
+					return
+				}
+			case "check-links":
+				if len(args) < 2 {
+					cmd.Help()
+					return
+				}
+				if err := checkLinks(cl, args[1:], format); err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+			case "pull":
+				if postSlug == "" || listSlug == "" {
+					cmd.Help()
+					return
+				}
+				if err := pullPost(cl, output, frontMatterMapping); err != nil {
 					fmt.Println(err)
 					return
 				}
@@ -151,6 +228,25 @@ func NewCmd() *cobra.Command {
 	cmd.Flags().StringVarP(&listSlug, "list", "l", "", "List slug")
 	cmd.Flags().StringVarP(&postSlug, "post", "p", "", "Post slug")
 	cmd.Flags().BoolVar(&doPublish, "publish", false, "Publish the post")
+	cmd.Flags().BoolVar(&isDraft, "draft", false, "Keep the post as an unpublished draft")
+	cmd.Flags().BoolVar(&doDeliver, "deliver", false, "Deliver the post to subscribers after publishing it, once")
+	cmd.Flags().BoolVar(&isPaid, "paid", false, "Make the whole post subscriber-only if it has no paywall marker")
+	cmd.Flags().StringVar(&canonical, "canonical-url", "", "Canonical URL of the post")
+	cmd.Flags().StringVar(&lang, "lang", "", "Language of the post")
+	cmd.Flags().StringVar(&summaryMode, "summary-mode", util.SummaryModeAuto, "How to fill the summary (auto: from the excerpt or first paragraph if missing, keep: use the frontmatter only, none: leave it empty)")
+	cmd.Flags().BoolVar(&writeSlug, "write-slug", false, "Write the generated slug back into the frontmatter of the file")
+	cmd.Flags().BoolVar(&writeBack, "write-back", false, "Write the id, slug and publish times assigned by Quail back into the frontmatter of the file")
+	cmd.Flags().StringVar(&titleOverride, "title", "", "Override the title in the frontmatter")
+	cmd.Flags().StringVar(&slugOverride, "slug", "", "Override the slug in the frontmatter")
+	cmd.Flags().StringVar(&tagsOverride, "tags", "", "Override the tags in the frontmatter, comma separated")
+	cmd.Flags().StringVar(&summaryOverride, "summary", "", "Override the summary in the frontmatter")
+	cmd.Flags().StringVar(&coverOverride, "cover", "", "Override the cover image URL in the frontmatter")
+	cmd.Flags().StringVar(&timezone, "tz", "", "Time zone of frontmatter datetimes without an offset, e.g. Asia/Shanghai (default is post.timezone in the config, or UTC)")
+	cmd.Flags().BoolVar(&normalizeCJK, "normalize-cjk", false, "Normalize the spacing, punctuation and quotes of Chinese and Japanese text in the body")
+	cmd.Flags().StringVar(&vaultDir, "vault", "", "Obsidian vault the file belongs to (default is post.vault.path in the config if it contains the file)")
+	cmd.Flags().BoolVar(&noPager, "no-pager", false, "Print the preview without a pager")
+	cmd.Flags().BoolVar(&remote, "remote", false, "Look up linked posts on Quail when checking links")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output file of pull (default is stdout)")
 
 	return cmd
 }
@@ -0,0 +1,158 @@
+package post
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/quail-ink/quail-cli/client"
+	"github.com/quail-ink/quail-cli/core"
+	"github.com/quail-ink/quail-cli/transform"
+	"github.com/quail-ink/quail-cli/util"
+	"golang.org/x/term"
+)
+
+// previewWidth is the width of the preview if stdout is not a terminal.
+const previewWidth = 80
+
+// previewPost renders a post in the terminal the way it will be sent: after the
+// transformers that work offline, with a header of its metadata. If stdout is a
+// terminal, the preview is shown in $PAGER.
+func previewPost(cl *client.Client, file string, frontMatterMapping core.FrontMatterMapping) error {
+	doc, frontMatter, err := readPost(file, frontMatterMapping)
+	if err != nil {
+		return err
+	}
+
+	// the obsidian and links transformers upload files and look up posts
+	pipeline, err := newPipeline(cl, file)
+	if err != nil {
+		return err
+	}
+	post := &transform.Post{List: frontMatter.List, FrontMatter: frontMatter, Params: doc.FrontMatter, Content: string(doc.Body)}
+	if file != "-" {
+		post.File = file
+	}
+	if err := pipeline.Without("obsidian", "links").Run(post); err != nil {
+		return err
+	}
+
+	markdown := previewHeader(post.FrontMatter, post.Content) + previewBody(post.Content)
+
+	isTerminal := term.IsTerminal(int(os.Stdout.Fd()))
+	width := previewWidth
+	if isTerminal {
+		if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+			width = w
+		}
+	}
+	style := "notty"
+	if isTerminal {
+		style = "auto"
+	}
+	renderer, err := glamour.NewTermRenderer(glamour.WithStandardStyle(style), glamour.WithWordWrap(width-4))
+	if err != nil {
+		return fmt.Errorf("could not create renderer: %w", err)
+	}
+	rendered, err := renderer.Render(markdown)
+	if err != nil {
+		return fmt.Errorf("could not render preview: %w", err)
+	}
+
+	if !isTerminal || noPager {
+		fmt.Print(rendered)
+		return nil
+	}
+	return page(rendered)
+}
+
+// previewHeader renders the metadata of a post, with the generated slug and summary
+// if the front matter has none.
+func previewHeader(frontMatter *core.QuailPostFrontMatter, content string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", frontMatter.Title)
+
+	slug := frontMatter.Slug
+	if slug == "" {
+		slug = util.SlugifyTitle(frontMatter.Title, slugMaxLength()) + " *(generated)*"
+	}
+	summary := frontMatter.Summary
+	if summary == "" {
+		summary = util.Summarize(content, summaryLength()) + " *(generated)*"
+	}
+	datetime := ""
+	if loc, err := location(); err == nil && frontMatter.Datetime != nil {
+		datetime = frontMatter.Datetime.In(loc).Format(time.RFC1123)
+	}
+	state := []string{}
+	if frontMatter.Draft {
+		state = append(state, "draft")
+	} else if frontMatter.Publish != nil && *frontMatter.Publish {
+		state = append(state, "publish")
+	}
+	if frontMatter.Deliver {
+		state = append(state, "deliver")
+	}
+	if frontMatter.Paid {
+		state = append(state, "paid")
+	}
+
+	fields := [][2]string{
+		{"List", frontMatter.List},
+		{"Slug", slug},
+		{"Datetime", datetime},
+		{"Tags", strings.ReplaceAll(frontMatter.Tags, ",", ", ")},
+		{"Summary", summary},
+		{"Cover", frontMatter.CoverImageUrl},
+		{"Lang", frontMatter.Lang},
+		{"Canonical URL", frontMatter.CanonicalURL},
+		{"State", strings.Join(state, ", ")},
+	}
+	for _, field := range fields {
+		if field[1] != "" {
+			fmt.Fprintf(&b, "- **%s:** %s\n", field[0], field[1])
+		}
+	}
+	fmt.Fprintf(&b, "- **Stats:** %s\n\n---\n\n", statsLine(content))
+	return b.String()
+}
+
+// previewBody marks where the paid content starts.
+func previewBody(content string) string {
+	free, paid := util.SplitPaidContent(content, paywallMarker())
+	if paid == "" {
+		return content
+	}
+	return free + "\n---\n\n*🔒 Paid subscribers only below*\n\n---\n\n" + paid
+}
+
+// page shows text in $PAGER, or less.
+func page(text string) error {
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		pager = "less"
+	}
+	args := strings.Fields(pager)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Env = os.Environ()
+	if os.Getenv("LESS") == "" {
+		// keep the colors and quit if the preview fits on one screen
+		cmd.Env = append(cmd.Env, "LESS=FRX")
+	}
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		// no pager, print it as is
+		if _, ok := err.(*exec.Error); ok {
+			_, err = io.WriteString(os.Stdout, text)
+			return err
+		}
+		return err
+	}
+	return nil
+}
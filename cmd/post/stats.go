@@ -0,0 +1,66 @@
+package post
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/quail-ink/quail-cli/client"
+	"github.com/quail-ink/quail-cli/cmd/common"
+	"github.com/quail-ink/quail-cli/core"
+	"github.com/quail-ink/quail-cli/util"
+)
+
+type postStats struct {
+	File  string `json:"file"`
+	Title string `json:"title"`
+	util.Stats
+}
+
+// statsPost prints the statistics of a Markdown file, or stdin if file is "-".
+func statsPost(file string, frontMatterMapping core.FrontMatterMapping, format string) error {
+	doc, frontMatter, err := readPost(file, frontMatterMapping)
+	if err != nil {
+		return err
+	}
+	stats := postStats{File: file, Title: frontMatter.Title, Stats: doc.Stats()}
+
+	if format == common.FORMAT_JSON {
+		client.PrettyPrintJSON(stats)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 1, ' ', 0)
+	fmt.Fprintln(w, "Stats:")
+	fmt.Fprintf(w, "File:\t%s\n", stats.File)
+	fmt.Fprintf(w, "Title:\t%s\n", stats.Title)
+	fmt.Fprintf(w, "Words:\t%d (%d Latin words, %d CJK characters)\n", stats.Words, stats.LatinWords, stats.CJKCharacters)
+	fmt.Fprintf(w, "Characters:\t%d\n", stats.Characters)
+	fmt.Fprintf(w, "Reading Time:\t%d min\n", stats.ReadingMinutes)
+	fmt.Fprintf(w, "Images:\t%d\n", stats.Images)
+	fmt.Fprintf(w, "Links:\t%d\n", stats.Links)
+	fmt.Fprintf(w, "Code Blocks:\t%d\n", stats.CodeBlocks)
+	fmt.Fprintf(w, "Email Size:\t%s\n", emailSize(stats.Stats))
+	fmt.Fprintf(w, "Outline:\n")
+	for _, heading := range stats.Headings {
+		fmt.Fprintf(w, "\t%s%s (line %d)\n", strings.Repeat("  ", heading.Level-1), heading.Text, heading.Line)
+	}
+	w.Flush()
+	return nil
+}
+
+func emailSize(stats util.Stats) string {
+	size := fmt.Sprintf("~%d KB", (stats.EmailSize+1023)/1024)
+	if stats.EmailClipped {
+		size += fmt.Sprintf(", over the %d KB Gmail clips", util.EmailClipSize/1024)
+	}
+	return size
+}
+
+// statsLine summarizes the statistics of content in one line.
+func statsLine(content string) string {
+	stats := (&util.Document{Body: []byte(content)}).Stats()
+	return fmt.Sprintf("%d words, %d min read, %d images, %d links, %d code blocks, email %s",
+		stats.Words, stats.ReadingMinutes, stats.Images, stats.Links, stats.CodeBlocks, emailSize(stats))
+}
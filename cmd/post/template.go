@@ -0,0 +1,81 @@
+package post
+
+import (
+	"os"
+	"strings"
+
+	"github.com/quail-ink/quail-cli/client"
+	"github.com/quail-ink/quail-cli/core"
+	"github.com/quail-ink/quail-cli/transform"
+	"github.com/quail-ink/quail-cli/util"
+	"github.com/spf13/viper"
+)
+
+// templateVarPrefix is the prefix of environment variables available as template vars,
+// e.g. QUAIL_VAR_VERSION is {{ .Vars.version }}.
+const templateVarPrefix = "QUAIL_VAR_"
+
+// templateData is the data of a post template. The user and the list are only
+// fetched if the template uses them.
+type templateData struct {
+	// Post is the front matter after the mapping and the flags are applied.
+	Post *core.QuailPostFrontMatter
+	// Params is the front matter as written in the file, including custom keys.
+	Params map[string]any
+	// Vars are `post.template.vars` from the config and QUAIL_VAR_* environment variables.
+	Vars map[string]any
+
+	cl   *client.Client
+	user *client.UserResponse
+	list *client.ListResponse
+}
+
+func (d *templateData) User() (any, error) {
+	if d.user == nil {
+		user, err := d.cl.GetMe()
+		if err != nil {
+			return nil, err
+		}
+		d.user = user
+	}
+	return d.user.Data, nil
+}
+
+func (d *templateData) List() (*client.List, error) {
+	if d.list == nil {
+		list, err := d.cl.GetList(d.Post.List)
+		if err != nil {
+			return nil, err
+		}
+		d.list = list
+	}
+	return &d.list.Data, nil
+}
+
+func templateVars() map[string]any {
+	vars := map[string]any{}
+	for key, value := range viper.GetStringMap("post.template.vars") {
+		vars[key] = value
+	}
+	for _, env := range os.Environ() {
+		key, value, _ := strings.Cut(env, "=")
+		if name, ok := strings.CutPrefix(key, templateVarPrefix); ok {
+			vars[strings.ToLower(name)] = value
+		}
+	}
+	return vars
+}
+
+// renderTemplate renders the body of a post with `template: true` as a text/template.
+func renderTemplate(cl *client.Client, post *transform.Post) (string, error) {
+	data := &templateData{
+		Post:   post.FrontMatter,
+		Params: post.Params,
+		Vars:   templateVars(),
+		cl:     cl,
+	}
+	if data.Params == nil {
+		data.Params = map[string]any{}
+	}
+	return util.RenderTemplate(post.Name(), post.Content, data)
+}
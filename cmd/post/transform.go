@@ -0,0 +1,111 @@
+package post
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/quail-ink/quail-cli/client"
+	"github.com/quail-ink/quail-cli/cmd/common"
+	"github.com/quail-ink/quail-cli/transform"
+	"github.com/quail-ink/quail-cli/util"
+	"github.com/spf13/viper"
+)
+
+// builtinTransformers are the steps every post goes through, in order. Steps that
+// need a file are skipped for stdin. Templates are rendered first, so included
+// files and converted shortcodes are not parsed as templates.
+func builtinTransformers(cl *client.Client) transform.Pipeline {
+	return transform.Pipeline{
+		transform.NewFunc("template", func(post *transform.Post) error {
+			if !post.FrontMatter.Template {
+				return nil
+			}
+			content, err := renderTemplate(cl, post)
+			post.Content = content
+			return err
+		}),
+		transform.NewFunc("include", func(post *transform.Post) error {
+			dir := "."
+			if post.File != "" {
+				dir = filepath.Dir(post.File)
+			}
+			content, err := util.ExpandIncludes(post.Content, dir)
+			post.Content = content
+			return err
+		}),
+		transform.NewFunc("shortcodes", func(post *transform.Post) error {
+			warn := func(msg string) {
+				fmt.Fprintf(os.Stderr, "warning: %s: %s\n", post.Name(), msg)
+			}
+			post.Content = util.ConvertShortcodes(post.Content, post.Params, warn)
+			// capitalized tags in plain Markdown are not necessarily components
+			isMDX := strings.EqualFold(filepath.Ext(post.File), ".mdx")
+			if isMDX || viper.GetBool("post.mdx") {
+				post.Content = util.ConvertMDX(post.Content, isMDX, warn)
+			}
+			return nil
+		}),
+		transform.NewFunc("obsidian", func(post *transform.Post) error {
+			root := vaultRoot(post.File)
+			if post.File == "" || root == "" {
+				return nil
+			}
+			content, err := convertVaultNote(cl, root, post)
+			post.Content = content
+			return err
+		}),
+		transform.NewFunc("callouts", func(post *transform.Post) error {
+			content, err := ConvertCallouts(post.Content)
+			post.Content = content
+			return err
+		}),
+		transform.NewFunc("links", func(post *transform.Post) error {
+			if post.File != "" {
+				post.Content = rewriteLocalLinks(cl, post.File, post.List, post.Content)
+			}
+			return nil
+		}),
+		transform.NewFunc("cjk", func(post *transform.Post) error {
+			if normalizeCJK {
+				post.Content = util.NormalizeCJK(post.Content)
+			}
+			return nil
+		}),
+		transform.NewFunc("toc", func(post *transform.Post) error {
+			if !post.FrontMatter.TOC {
+				return nil
+			}
+			depth := post.FrontMatter.TOCDepth
+			if depth == 0 {
+				depth = viper.GetInt("post.toc.depth")
+			}
+			anchors := !viper.IsSet("post.toc.anchors") || viper.GetBool("post.toc.anchors")
+			post.Content = util.InsertTOC(post.Content, depth, anchors)
+			return nil
+		}),
+	}
+}
+
+// newPipeline returns the built-in transformers followed by the external transformers
+// of the project of file.
+func newPipeline(cl *client.Client, file string) (transform.Pipeline, error) {
+	project, err := common.LoadProject(file)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := location()
+	if err != nil {
+		return nil, err
+	}
+	pipeline := builtinTransformers(cl).Without(project.DisableTransformers...)
+	for _, step := range project.Transformers {
+		t, err := transform.NewExec(step, project.Root, loc)
+		if err != nil {
+			return nil, err
+		}
+		pipeline = append(pipeline, t)
+	}
+	return pipeline, nil
+}
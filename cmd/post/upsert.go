@@ -0,0 +1,335 @@
+package post
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/quail-ink/quail-cli/client"
+	"github.com/quail-ink/quail-cli/cmd/common"
+	"github.com/quail-ink/quail-cli/core"
+	"github.com/quail-ink/quail-cli/transform"
+	"github.com/quail-ink/quail-cli/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// UpsertOptions controls how Upsert turns a Markdown post into a request.
+type UpsertOptions struct {
+	Publish bool
+	// SummaryMode is one of util.SummaryModeAuto, util.SummaryModeKeep and util.SummaryModeNone.
+	SummaryMode string
+}
+
+func upsertPost(cmd *cobra.Command, cl *client.Client, file string, frontMatterMapping core.FrontMatterMapping, format string) error {
+	if file == "" {
+		return fmt.Errorf("filepath is required")
+	}
+
+	fromStdin := file == "-"
+	if fromStdin && (writeBack || writeSlug) {
+		return fmt.Errorf("--write-back and --write-slug need a file, not stdin")
+	}
+
+	doc, frontMatter, err := readPost(file, frontMatterMapping)
+	if err != nil {
+		return err
+	}
+	if err := applyOverrides(cmd, frontMatter); err != nil {
+		return err
+	}
+
+	list := frontMatter.List
+	if list == "" {
+		return fmt.Errorf("list is required, use -l or the list field of the frontmatter")
+	}
+	pipeline, err := newPipeline(cl, file)
+	if err != nil {
+		return err
+	}
+	post := &transform.Post{List: list, FrontMatter: frontMatter, Params: doc.FrontMatter, Content: string(doc.Body)}
+	if !fromStdin {
+		post.File = file
+	}
+	if err := pipeline.Run(post); err != nil {
+		return err
+	}
+	frontMatter, content := post.FrontMatter, post.Content
+	publish := frontMatter.Publish != nil && *frontMatter.Publish && !frontMatter.Draft
+	deliver := frontMatter.Deliver && publish && frontMatter.DeliveredAt == nil
+	// without delivered_at in the file, every upsert would email the subscribers again
+	if deliver && !writeBack && !cmd.Flags().Changed("deliver") {
+		return fmt.Errorf("deliver is set but delivered_at cannot be recorded, use --write-back, or --deliver to deliver anyway")
+	}
+
+	generatedSlug := frontMatter.Slug == ""
+	if frontMatter.RelativeDatetime && !writeBack {
+		fmt.Fprintln(os.Stderr, "warning: datetime is relative and is evaluated again on the next upsert, use --write-back to pin it in the file")
+	}
+
+	result, err := Upsert(cl, list, frontMatter, content, UpsertOptions{
+		Publish:     publish,
+		SummaryMode: summaryMode,
+	})
+	if err != nil {
+		return err
+	}
+
+	if frontMatter.Draft && !result.Data.PublishedAt.IsZero() {
+		if result, err = cl.ModPost(list, result.Data.Slug, "unpublish"); err != nil {
+			return err
+		}
+	}
+
+	delivered := false
+	if deliver {
+		if result, err = cl.ModPost(list, result.Data.Slug, "deliver"); err != nil {
+			return err
+		}
+		delivered = true
+		if fromStdin || !writeBack {
+			fmt.Fprintln(os.Stderr, "Delivered. Set deliver to false or use --write-back before upserting this post again, or it is delivered again.")
+		}
+	}
+
+	values := map[string]any{}
+	if writeBack {
+		values = writeBackValues(frontMatter, result)
+	} else if writeSlug && generatedSlug && frontMatter.Slug != "" {
+		values["slug"] = frontMatter.Slug
+	}
+	if delivered && writeBack {
+		values["delivered_at"] = time.Now().Format(time.RFC3339)
+	}
+	if len(values) > 0 && !fromStdin {
+		mapped := map[string]any{}
+		for key, value := range values {
+			mapped[frontMatterMapping.Name(key)] = value
+		}
+		if err := util.UpdateFrontMatterFile(file, mapped); err != nil {
+			return err
+		}
+	}
+
+	if format == common.FORMAT_JSON {
+		client.PrettyPrintJSON(result)
+	} else {
+		client.PrettyPrintPost(result)
+		fmt.Println(statsLine(content))
+	}
+
+	return nil
+}
+
+// readPost parses a Markdown file, or stdin if filepath is "-".
+func readPost(filepath string, frontMatterMapping core.FrontMatterMapping) (*util.Document, *core.QuailPostFrontMatter, error) {
+	var doc *util.Document
+	if filepath == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not read stdin: %w", err)
+		}
+		if doc, err = util.ParseDocument(data); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		var err error
+		if doc, err = util.ReadDocument(filepath); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	loc, err := location()
+	if err != nil {
+		return nil, nil, err
+	}
+	frontMatter, err := doc.QuailFrontMatter(frontMatterMapping, loc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return doc, frontMatter, nil
+}
+
+// applyOverrides replaces front matter fields with the ones given as flags.
+func applyOverrides(cmd *cobra.Command, frontMatter *core.QuailPostFrontMatter) error {
+	if titleOverride != "" {
+		frontMatter.Title = titleOverride
+	}
+	if slugOverride != "" {
+		frontMatter.Slug = slugOverride
+	}
+	if tagsOverride != "" {
+		frontMatter.Tags = core.ParseTags(tagsOverride)
+	}
+	if summaryOverride != "" {
+		frontMatter.Summary = summaryOverride
+	}
+	if coverOverride != "" {
+		frontMatter.CoverImageUrl = coverOverride
+	}
+	if canonical != "" {
+		frontMatter.CanonicalURL = canonical
+	}
+	if lang != "" {
+		frontMatter.Lang = lang
+	}
+	if listSlug != "" {
+		frontMatter.List = listSlug
+	}
+
+	// boolean flags only override the front matter if they are given
+	flags := cmd.Flags()
+	if flags.Changed("publish") && flags.Changed("draft") && doPublish && isDraft {
+		return fmt.Errorf("--publish and --draft cannot be used together")
+	}
+	if flags.Changed("publish") {
+		frontMatter.Publish = &doPublish
+		// publishing on the command line wins over a draft in the file
+		if doPublish {
+			frontMatter.Draft = false
+		}
+	}
+	if flags.Changed("draft") {
+		frontMatter.Draft = isDraft
+	}
+	if flags.Changed("deliver") {
+		frontMatter.Deliver = doDeliver
+	}
+	if flags.Changed("paid") {
+		frontMatter.Paid = isPaid
+	}
+	return nil
+}
+
+// writeBackValues returns the fields assigned by Quail that differ from the front matter.
+// A relative datetime is always replaced with the time it resolved to.
+func writeBackValues(frontMatter *core.QuailPostFrontMatter, result *client.PostResponse) map[string]any {
+	post := result.Data
+	values := map[string]any{}
+	if post.ID != 0 && post.ID != frontMatter.ID {
+		values["id"] = post.ID
+	}
+	if post.Slug != "" && post.Slug != frontMatter.Slug {
+		values["slug"] = post.Slug
+	}
+	switch {
+	case !post.FirstPublishedAt.IsZero() && (frontMatter.RelativeDatetime || frontMatter.Datetime == nil || !frontMatter.Datetime.Equal(post.FirstPublishedAt)):
+		values["datetime"] = post.FirstPublishedAt.Format(time.RFC3339)
+	case frontMatter.RelativeDatetime && frontMatter.Datetime != nil:
+		values["datetime"] = frontMatter.Datetime.Format(time.RFC3339)
+	}
+	if !post.PublishedAt.IsZero() && (frontMatter.PublishedAt == nil || !frontMatter.PublishedAt.Equal(post.PublishedAt)) {
+		values["published_at"] = post.PublishedAt.Format(time.RFC3339)
+	}
+	return values
+}
+
+func paywallMarker() string {
+	if marker := viper.GetString("post.paywall_marker"); marker != "" {
+		return marker
+	}
+	return util.DefaultPaywallMarker
+}
+
+func summaryLength() int {
+	if length := viper.GetInt("post.summary_length"); length > 0 {
+		return length
+	}
+	return util.DefaultSummaryLength
+}
+
+// location returns the time zone of frontmatter datetimes without an offset, from
+// --tz or `post.timezone`, or UTC.
+func location() (*time.Location, error) {
+	name := timezone
+	if name == "" {
+		name = viper.GetString("post.timezone")
+	}
+	return core.LoadLocation(name)
+}
+
+func slugMaxLength() int {
+	if length := viper.GetInt("post.slug_max_length"); length > 0 {
+		return length
+	}
+	return util.DefaultSlugMaxLength
+}
+
+// uniqueSlug returns slug, or slug with a numeric suffix if another post in the
+// list already uses it. A post with the same title is taken to be this post.
+func uniqueSlug(cl *client.Client, list, slug, title string) (string, error) {
+	candidate := slug
+	for i := 2; i < 100; i++ {
+		existing, err := cl.GetPost(list, candidate)
+		if err != nil {
+			return "", fmt.Errorf("could not check slug %s: %w", candidate, err)
+		}
+		if existing.Data.ID == 0 || existing.Data.Title == title {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", slug, i)
+	}
+	return "", fmt.Errorf("could not find a free slug for %s", slug)
+}
+
+// Upsert creates or updates a post in the list from the front matter and Markdown content.
+// It is shared by every command that sends posts to Quail. If the front matter has no
+// slug, one is generated from the title and set on frontMatter.
+func Upsert(cl *client.Client, list string, frontMatter *core.QuailPostFrontMatter, content string, opts UpsertOptions) (*client.PostResponse, error) {
+	if frontMatter.Slug == "" && frontMatter.Title != "" {
+		slug, err := uniqueSlug(cl, list, util.SlugifyTitle(frontMatter.Title, slugMaxLength()), frontMatter.Title)
+		if err != nil {
+			return nil, err
+		}
+		frontMatter.Slug = slug
+	}
+
+	var datetime *time.Time
+	if opts.Publish {
+		datetime = frontMatter.Datetime
+		if datetime == nil {
+			now := time.Now()
+			datetime = &now
+		}
+	}
+
+	content, paidContent := util.SplitPaidContent(content, paywallMarker())
+	if frontMatter.Paid && paidContent == "" {
+		content, paidContent = "", content
+	}
+
+	// the summary is public, so it is only taken from the free content
+	summary := frontMatter.Summary
+	switch opts.SummaryMode {
+	case util.SummaryModeNone:
+		summary = ""
+	case util.SummaryModeKeep:
+	case util.SummaryModeAuto, "":
+		if summary == "" {
+			summary = util.Summarize(content, summaryLength())
+		}
+	default:
+		return nil, fmt.Errorf("unknown summary mode: %s", opts.SummaryMode)
+	}
+	// the excerpt marker is only a hint for the summary
+	_, content, _ = util.SplitExcerpt(content)
+	_, paidContent, _ = util.SplitExcerpt(paidContent)
+
+	payload := map[string]any{
+		"slug":               frontMatter.Slug,
+		"cover_image_url":    frontMatter.CoverImageUrl,
+		"title":              frontMatter.Title,
+		"summary":            summary,
+		"content":            content,
+		"paid_content":       paidContent,
+		"datetime":           datetime,
+		"first_published_at": frontMatter.Datetime,
+		"tags":               frontMatter.Tags,
+		"theme":              frontMatter.Theme,
+		"canonical_url":      frontMatter.CanonicalURL,
+		"lang":               frontMatter.Lang,
+	}
+
+	return cl.CreatePost(list, payload)
+}
@@ -0,0 +1,151 @@
+package post
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/quail-ink/quail-cli/client"
+	"github.com/quail-ink/quail-cli/cmd/common"
+	"github.com/quail-ink/quail-cli/core"
+	"github.com/quail-ink/quail-cli/transform"
+	"github.com/quail-ink/quail-cli/util"
+	"github.com/spf13/viper"
+)
+
+// vaultRoot returns the absolute path of the Obsidian vault of file: the --vault flag,
+// or `post.vault.path` if it contains file. It is empty if file is not in a vault.
+func vaultRoot(file string) string {
+	if vaultDir != "" {
+		root, err := filepath.Abs(expandHome(vaultDir))
+		if err != nil {
+			return vaultDir
+		}
+		return root
+	}
+	root := viper.GetString("post.vault.path")
+	if root == "" || file == "" || file == "-" {
+		return ""
+	}
+	root, err := filepath.Abs(expandHome(root))
+	if err != nil {
+		return ""
+	}
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(root, absFile)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	return root
+}
+
+// expandHome replaces a leading ~ with the home directory of the user.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
+// defaultPublishFlag marks the notes of a vault to upload. It is not `publish`,
+// which publishes the post, so a note can be uploaded as a draft.
+const defaultPublishFlag = "quail_publish"
+
+// publishFlag is the frontmatter key that marks the notes of a vault to upload.
+func publishFlag() string {
+	if flag := viper.GetString("post.vault.publish_flag"); flag != "" {
+		return flag
+	}
+	return defaultPublishFlag
+}
+
+func isMarkedForPublishing(doc *util.Document) bool {
+	switch v := doc.FrontMatter[publishFlag()].(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(v, "true") || v == "yes"
+	}
+	return false
+}
+
+// noteTitle returns the title of a note, which is its file name unless the
+// frontmatter has one, as in Obsidian.
+func noteTitle(file string, frontMatter *core.QuailPostFrontMatter) string {
+	if frontMatter.Title != "" {
+		return frontMatter.Title
+	}
+	return strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+}
+
+// convertVaultNote converts the Obsidian syntax of a note to Markdown. Wikilinks point
+// to the posts of the notes marked for publishing, attachments are uploaded and
+// notes are transcluded. Notes that are not marked for publishing are rejected.
+func convertVaultNote(cl *client.Client, root string, post *transform.Post) (string, error) {
+	file, list, frontMatter := post.File, post.List, post.FrontMatter
+	doc, err := util.ReadDocument(file)
+	if err != nil {
+		return "", err
+	}
+	if !isMarkedForPublishing(doc) {
+		return "", fmt.Errorf("%s is not marked for publishing, set %s: true in its frontmatter", file, publishFlag())
+	}
+	frontMatter.Title = noteTitle(file, frontMatter)
+
+	vault, err := util.OpenVault(root)
+	if err != nil {
+		return "", err
+	}
+	vault.Warn = func(msg string) {
+		fmt.Fprintln(os.Stderr, "warning:", msg)
+	}
+	vault.Upload = func(path string) (string, error) {
+		f, err := os.Open(path)
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+		result, err := cl.UploadAttachment(filepath.Base(path), f)
+		if err != nil {
+			return "", err
+		}
+		return result.Data.URL, nil
+	}
+	vault.NoteURL = func(path string) (string, bool) {
+		doc, err := util.ReadDocument(path)
+		if err != nil || !isMarkedForPublishing(doc) {
+			return "", false
+		}
+		mapping, err := common.FrontMatterMapping(path)
+		if err != nil {
+			return "", false
+		}
+		loc, err := location()
+		if err != nil {
+			return "", false
+		}
+		target, err := doc.QuailFrontMatter(mapping, loc)
+		if err != nil {
+			return "", false
+		}
+		slug := target.Slug
+		if slug == "" {
+			slug = util.SlugifyTitle(noteTitle(path, target), slugMaxLength())
+		}
+		targetList := target.List
+		if targetList == "" {
+			targetList = list
+		}
+		return core.PostURL(siteURL(), targetList, slug), true
+	}
+
+	return vault.Convert(file, post.Content)
+}
@@ -0,0 +1,242 @@
+package restore
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/quail-ink/quail-cli/archive"
+	"github.com/quail-ink/quail-cli/client"
+	"github.com/quail-ink/quail-cli/cmd/common"
+	"github.com/quail-ink/quail-cli/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dryRun        bool
+	listMap       []string
+	keepImageURLs bool
+)
+
+type action struct {
+	Op    string `json:"op"`
+	List  string `json:"list"`
+	Post  string `json:"post,omitempty"`
+	Title string `json:"title"`
+	// Images is the number of archived images of the post that are uploaded again.
+	Images int    `json:"images,omitempty"`
+	Status string `json:"status"`
+}
+
+// imageUploader uploads the archived images to the current account, each only once.
+type imageUploader struct {
+	cl       *client.Client
+	b        *archive.Backup
+	uploaded map[string]string
+}
+
+// postImages returns the original URLs of the archived images used by post.
+func (u *imageUploader) postImages(post client.Post) []string {
+	urls := []string{}
+	seen := map[string]bool{}
+	candidates := append([]string{post.CoverImageURL}, util.ExtractImageURLs(post.Content)...)
+	candidates = append(candidates, util.ExtractImageURLs(post.PaidContent)...)
+	for _, url := range candidates {
+		if _, ok := u.b.Manifest.Images[url]; ok && !seen[url] {
+			seen[url] = true
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
+
+// rewrite uploads the images of post and points post to their new URLs.
+func (u *imageUploader) rewrite(post *client.Post, urls []string) error {
+	for _, url := range urls {
+		newURL, ok := u.uploaded[url]
+		if !ok {
+			name := u.b.Manifest.Images[url]
+			result, err := u.cl.UploadAttachment(path.Base(name), bytes.NewReader(u.b.Images[name]))
+			if err != nil {
+				return fmt.Errorf("could not upload image %s: %w", url, err)
+			}
+			newURL = result.Data.URL
+			u.uploaded[url] = newURL
+		}
+		if post.CoverImageURL == url {
+			post.CoverImageURL = newURL
+		}
+		post.Content = strings.ReplaceAll(post.Content, url, newURL)
+		post.PaidContent = strings.ReplaceAll(post.PaidContent, url, newURL)
+	}
+	return nil
+}
+
+func parseListMap(pairs []string) (map[string]string, error) {
+	m := map[string]string{}
+	for _, pair := range pairs {
+		from, to, ok := strings.Cut(pair, "=")
+		if !ok || from == "" || to == "" {
+			return nil, fmt.Errorf("invalid list mapping %q, expected old=new", pair)
+		}
+		m[from] = to
+	}
+	return m, nil
+}
+
+func postPayload(post client.Post) map[string]any {
+	payload := map[string]any{
+		"slug":            post.Slug,
+		"cover_image_url": post.CoverImageURL,
+		"title":           post.Title,
+		"summary":         post.Summary,
+		"content":         post.Content,
+		"paid_content":    post.PaidContent,
+		"tags":            post.Tags,
+		"theme":           post.Theme,
+	}
+	if !post.PublishedAt.IsZero() {
+		payload["datetime"] = post.PublishedAt
+	}
+	if !post.FirstPublishedAt.IsZero() {
+		payload["first_published_at"] = post.FirstPublishedAt
+	}
+	return payload
+}
+
+// restore creates the lists and posts of b. If b is from another account, the
+// archived images are uploaded again and the posts point to the new URLs.
+func restore(cl *client.Client, b *archive.Backup, mapping map[string]string) ([]action, error) {
+	actions := []action{}
+
+	var uploader *imageUploader
+	if !keepImageURLs && len(b.Images) > 0 {
+		me, err := cl.GetMe()
+		if err != nil {
+			return actions, fmt.Errorf("could not get user: %w", err)
+		}
+		if me.Data.ID != b.Manifest.UserID {
+			uploader = &imageUploader{cl: cl, b: b, uploaded: map[string]string{}}
+		}
+	}
+
+	for _, item := range b.Lists {
+		slug := item.List.Slug
+		if target, ok := mapping[slug]; ok {
+			slug = target
+		}
+
+		existing, err := cl.GetList(slug)
+		if err != nil {
+			return actions, fmt.Errorf("could not get list %s: %w", slug, err)
+		}
+
+		act := action{Op: "create list", List: slug, Title: item.List.Title, Status: "pending"}
+		if existing.Data.ID != 0 {
+			act.Op = "reuse list"
+			act.Status = "exists"
+		} else if !dryRun {
+			_, err := cl.CreateList(map[string]any{
+				"slug":        slug,
+				"title":       item.List.Title,
+				"description": item.List.Description,
+				"lang":        item.List.Lang,
+			})
+			if err != nil {
+				return actions, fmt.Errorf("could not create list %s: %w", slug, err)
+			}
+			act.Status = "done"
+		}
+		actions = append(actions, act)
+
+		for _, post := range item.Posts {
+			act := action{Op: "upsert post", List: slug, Post: post.Slug, Title: post.Title, Status: "pending"}
+			var images []string
+			if uploader != nil {
+				images = uploader.postImages(post)
+				act.Images = len(images)
+			}
+			if !dryRun {
+				if len(images) > 0 {
+					if err := uploader.rewrite(&post, images); err != nil {
+						return actions, fmt.Errorf("could not restore post %s/%s: %w", slug, post.Slug, err)
+					}
+				}
+				if _, err := cl.CreatePost(slug, postPayload(post)); err != nil {
+					return actions, fmt.Errorf("could not restore post %s/%s: %w", slug, post.Slug, err)
+				}
+				act.Status = "done"
+			}
+			actions = append(actions, act)
+		}
+	}
+	return actions, nil
+}
+
+func printActions(actions []action) {
+	w := tabwriter.NewWriter(os.Stdout, 1, 1, 1, ' ', 0)
+	fmt.Fprintln(w, "Action\tList\tPost\tTitle\tImages\tStatus")
+	for _, act := range actions {
+		images := ""
+		if act.Images > 0 {
+			images = fmt.Sprint(act.Images)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", act.Op, act.List, act.Post, act.Title, images, act.Status)
+	}
+	w.Flush()
+}
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore <archive>",
+		Short: "Restore lists and posts from a backup archive into the current account",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 0 {
+				cmd.Help()
+				return
+			}
+
+			cl := cmd.Context().Value(common.CTX_CLIENT{}).(*client.Client)
+			format := cmd.Context().Value(common.CTX_FORMAT{}).(string)
+
+			mapping, err := parseListMap(listMap)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+
+			file, err := os.Open(args[0])
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+			defer file.Close()
+
+			b, err := archive.Read(file)
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+
+			actions, err := restore(cl, b, mapping)
+			if format == common.FORMAT_JSON {
+				client.PrettyPrintJSON(actions)
+			} else {
+				printActions(actions)
+			}
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only report what would be created")
+	cmd.Flags().BoolVar(&keepImageURLs, "keep-image-urls", false, "Do not upload archived images again when restoring into another account")
+	cmd.Flags().StringSliceVar(&listMap, "list-map", nil, "Restore a list under another slug, e.g. --list-map old=new")
+
+	return cmd
+}
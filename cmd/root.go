@@ -11,10 +11,13 @@ import (
 	"time"
 
 	"github.com/quail-ink/quail-cli/client"
+	"github.com/quail-ink/quail-cli/cmd/backup"
 	"github.com/quail-ink/quail-cli/cmd/common"
+	"github.com/quail-ink/quail-cli/cmd/importer"
 	"github.com/quail-ink/quail-cli/cmd/login"
 	"github.com/quail-ink/quail-cli/cmd/me"
 	"github.com/quail-ink/quail-cli/cmd/post"
+	"github.com/quail-ink/quail-cli/cmd/restore"
 	"github.com/quail-ink/quail-cli/oauth"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -70,6 +73,9 @@ func init() {
 	rootCmd.AddCommand(login.NewCmd())
 	rootCmd.AddCommand(me.NewCmd())
 	rootCmd.AddCommand(post.NewCmd())
+	rootCmd.AddCommand(importer.NewCmd())
+	rootCmd.AddCommand(backup.NewCmd())
+	rootCmd.AddCommand(restore.NewCmd())
 }
 
 func initConfig() {
@@ -0,0 +1,155 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Now returns the current time that relative datetimes are based on.
+var Now = time.Now
+
+var datetimeFormats = []string{
+	// add more datetime formats here
+	time.RFC1123,
+	time.RFC3339,          // 2006-01-02T15:04:05Z07:00
+	"2006-01-02T15:04:05", // TOML local datetime
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"02 Jan 2006",
+	"02 Jan 2006 15:04",
+	"02 Jan 2006 15:04:05",
+	"January 2, 2006",
+	"January 2, 2006 15:04",
+	"January 2, 2006 15:04:05",
+}
+
+var (
+	relativeDayRe      = regexp.MustCompile(`^(today|tomorrow|yesterday)(?:\s+(?:at\s+)?(.+))?$`)
+	relativeWeekdayRe  = regexp.MustCompile(`^(?:next\s+)?(monday|tuesday|wednesday|thursday|friday|saturday|sunday)(?:\s+(?:at\s+)?(.+))?$`)
+	relativeDurationRe = regexp.MustCompile(`^in\s+(\d+)\s+(minute|hour|day|week)s?$`)
+	clockRe            = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?\s*(am|pm)?$`)
+
+	weekdays = map[string]time.Weekday{
+		"sunday": time.Sunday, "monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday,
+		"thursday": time.Thursday, "friday": time.Friday, "saturday": time.Saturday,
+	}
+	durationUnits = map[string]time.Duration{
+		"minute": time.Minute, "hour": time.Hour, "day": 24 * time.Hour, "week": 7 * 24 * time.Hour,
+	}
+)
+
+// LoadLocation loads a time zone by its IANA name, e.g. Asia/Shanghai.
+// "Local" is the time zone of this machine and "" is UTC.
+func LoadLocation(name string) (*time.Location, error) {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone: %s", name)
+	}
+	return loc, nil
+}
+
+// ParseDateTime parses an absolute datetime in one of datetimeFormats, or a relative
+// one such as "tomorrow 09:00", "next monday" or "in 2 hours". Datetimes without
+// an offset are in loc, or UTC if loc is nil.
+func ParseDateTime(datetimeStr string, loc *time.Location) (*time.Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	datetimeStr = strings.TrimSpace(datetimeStr)
+	if parsedTime, ok := parseAbsoluteDateTime(datetimeStr, loc); ok {
+		return &parsedTime, nil
+	}
+	if parsedTime, ok := parseRelativeDateTime(strings.ToLower(datetimeStr), Now().In(loc)); ok {
+		return &parsedTime, nil
+	}
+	return nil, fmt.Errorf("could not parse datetime: %s", datetimeStr)
+}
+
+// IsRelativeDateTime reports whether datetimeStr is a relative datetime, which
+// ParseDateTime resolves to a different time depending on when it is called.
+func IsRelativeDateTime(datetimeStr string) bool {
+	datetimeStr = strings.TrimSpace(datetimeStr)
+	if _, ok := parseAbsoluteDateTime(datetimeStr, time.UTC); ok {
+		return false
+	}
+	_, ok := parseRelativeDateTime(strings.ToLower(datetimeStr), Now())
+	return ok
+}
+
+func parseAbsoluteDateTime(s string, loc *time.Location) (time.Time, bool) {
+	for _, layout := range datetimeFormats {
+		if parsedTime, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return parsedTime, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func parseRelativeDateTime(s string, now time.Time) (time.Time, bool) {
+	s = strings.Join(strings.Fields(s), " ")
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	if s == "now" {
+		return now, true
+	}
+
+	if m := relativeDurationRe.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return now.Add(time.Duration(n) * durationUnits[m[2]]), true
+	}
+
+	day, clock := time.Time{}, ""
+	if m := relativeDayRe.FindStringSubmatch(s); m != nil {
+		offset := map[string]int{"yesterday": -1, "today": 0, "tomorrow": 1}[m[1]]
+		day, clock = midnight.AddDate(0, 0, offset), m[2]
+	} else if m := relativeWeekdayRe.FindStringSubmatch(s); m != nil {
+		// the next such weekday after today
+		days := (int(weekdays[m[1]]) - int(now.Weekday()) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		day, clock = midnight.AddDate(0, 0, days), m[2]
+	} else {
+		return time.Time{}, false
+	}
+
+	if clock == "" {
+		return day, true
+	}
+	hour, minute, ok := parseClock(clock)
+	if !ok {
+		return time.Time{}, false
+	}
+	// not day.Add, which is off by an hour on the days daylight saving time changes
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, now.Location()), true
+}
+
+// parseClock parses 9, 09:00, 9am or 9:30pm.
+func parseClock(s string) (hour, minute int, ok bool) {
+	m := clockRe.FindStringSubmatch(s)
+	if m == nil {
+		return 0, 0, false
+	}
+	hour, _ = strconv.Atoi(m[1])
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+	switch m[3] {
+	case "am":
+		if hour == 12 {
+			hour = 0
+		}
+	case "pm":
+		if hour < 12 {
+			hour += 12
+		}
+	}
+	if hour > 23 || minute > 59 {
+		return 0, 0, false
+	}
+	return hour, minute, true
+}
@@ -0,0 +1,92 @@
+package core
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestParseDateTime(t *testing.T) {
+	shanghai, err := LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newYork, err := LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(now func() time.Time) { Now = now }(Now)
+	// a Saturday, the day before daylight saving time starts in New York
+	Now = func() time.Time { return time.Date(2024, 3, 9, 12, 30, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name    string
+		input   string
+		loc     *time.Location
+		want    time.Time
+		wantErr bool
+	}{
+		{"rfc3339", "2024-05-01T10:00:00+02:00", shanghai, time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC), false},
+		{"local datetime", "2024-05-01 10:00", shanghai, time.Date(2024, 5, 1, 10, 0, 0, 0, shanghai), false},
+		{"date", "2024-05-01", time.UTC, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), false},
+		{"long date", "May 1, 2024 10:00", time.UTC, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), false},
+		{"nil location", "2024-05-01 10:00", nil, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), false},
+		{"now", "now", time.UTC, time.Date(2024, 3, 9, 12, 30, 0, 0, time.UTC), false},
+		{"today", "today", time.UTC, time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC), false},
+		{"tomorrow at", "Tomorrow at 9am", time.UTC, time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC), false},
+		{"yesterday", "yesterday 21:15", time.UTC, time.Date(2024, 3, 8, 21, 15, 0, 0, time.UTC), false},
+		{"pm", "today 9:30pm", time.UTC, time.Date(2024, 3, 9, 21, 30, 0, 0, time.UTC), false},
+		{"next weekday", "next monday", time.UTC, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), false},
+		{"same weekday", "saturday 10:00", time.UTC, time.Date(2024, 3, 16, 10, 0, 0, 0, time.UTC), false},
+		{"in duration", "in 2 hours", time.UTC, time.Date(2024, 3, 9, 14, 30, 0, 0, time.UTC), false},
+		{"relative in location", "tomorrow 09:00", shanghai, time.Date(2024, 3, 10, 9, 0, 0, 0, shanghai), false},
+		{"daylight saving time", "tomorrow 09:00", newYork, time.Date(2024, 3, 10, 9, 0, 0, 0, newYork), false},
+		{"invalid clock", "tomorrow 25:00", time.UTC, time.Time{}, true},
+		{"invalid", "someday", time.UTC, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDateTime(tt.input, tt.loc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDateTime(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseDateTime(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadLocation(t *testing.T) {
+	if _, err := LoadLocation("Mars/Olympus"); err == nil {
+		t.Error("LoadLocation() of an unknown time zone did not fail")
+	}
+	if loc, err := LoadLocation(""); err != nil || loc != time.UTC {
+		t.Errorf("LoadLocation(\"\") = %v, %v, want UTC", loc, err)
+	}
+}
+
+func TestIsRelativeDateTime(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"2024-09-30 18:42", false},
+		{"2024-09-30T18:42:05+08:00", false},
+		{"30 Sep 2024", false},
+		{"now", true},
+		{"tomorrow 09:00", true},
+		{" Next Monday ", true},
+		{"in 2 hours", true},
+		{"not a datetime", false},
+	}
+	for _, tt := range tests {
+		if got := IsRelativeDateTime(tt.input); got != tt.want {
+			t.Errorf("IsRelativeDateTime(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type (
+	// FrontMatterMapping maps the standard front matter keys, such as cover_image_url,
+	// to the keys a file actually uses. It is configured in `post.frontmatter_mapping`:
+	//
+	//	frontmatter_mapping:
+	//	  # rename: use featureImage as cover_image_url
+	//	  cover_image_url: featureImage
+	//	  # fallbacks: the first key found is used, then summary itself
+	//	  summary: [description, excerpt]
+	//	  # dotted paths into nested tables and value transforms
+	//	  tags:
+	//	    from: [taxonomies.tags]
+	//	    transforms: ["strip:#", lowercase]
+	FrontMatterMapping map[string]FieldMapping
+
+	FieldMapping struct {
+		// From are the keys or dotted paths to read the field from, in order of preference.
+		From []string
+		// Transforms are applied to the value in order, see applyTransform.
+		Transforms []string
+	}
+)
+
+// ParseFrontMatterMapping parses the `frontmatter_mapping` section of the config file,
+// where each field is a key, a list of keys, or a map with `from` and `transforms`.
+func ParseFrontMatterMapping(raw any) (FrontMatterMapping, error) {
+	mapping := FrontMatterMapping{}
+	if raw == nil {
+		return mapping, nil
+	}
+	fields, ok := toStringMap(raw)
+	if !ok {
+		return nil, fmt.Errorf("frontmatter_mapping is not a map")
+	}
+
+	for key, value := range fields {
+		field := FieldMapping{}
+		switch v := value.(type) {
+		case string:
+			field.From = []string{v}
+		case []any:
+			field.From = toStrings(v)
+		default:
+			m, ok := toStringMap(v)
+			if !ok {
+				return nil, fmt.Errorf("invalid frontmatter_mapping for %s", key)
+			}
+			switch from := m["from"].(type) {
+			case string:
+				field.From = []string{from}
+			case []any:
+				field.From = toStrings(from)
+			}
+			if transforms, ok := m["transforms"].([]any); ok {
+				field.Transforms = toStrings(transforms)
+			}
+			for _, transform := range field.Transforms {
+				if _, err := applyTransform(transform, ""); err != nil {
+					return nil, fmt.Errorf("invalid frontmatter_mapping for %s: %w", key, err)
+				}
+			}
+		}
+		mapping[key] = field
+	}
+	return mapping, nil
+}
+
+// Merge returns a mapping with the fields of override replacing those of m.
+func (m FrontMatterMapping) Merge(override FrontMatterMapping) FrontMatterMapping {
+	merged := FrontMatterMapping{}
+	for key, field := range m {
+		merged[key] = field
+	}
+	for key, field := range override {
+		merged[key] = field
+	}
+	return merged
+}
+
+// Name returns the key under which the field is written back to a file: the first
+// top-level key it is read from, or the standard key itself.
+func (m FrontMatterMapping) Name(key string) string {
+	for _, from := range m[key].From {
+		if !strings.Contains(from, ".") {
+			return from
+		}
+	}
+	return key
+}
+
+// Apply returns a copy of frontMatterMap with the mapped fields under their
+// standard keys and transformed.
+func (m FrontMatterMapping) Apply(frontMatterMap map[string]any) (map[string]any, error) {
+	result := make(map[string]any, len(frontMatterMap))
+	for key, value := range frontMatterMap {
+		result[key] = value
+	}
+
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		field := m[key]
+		for _, from := range field.From {
+			if value, ok := lookupPath(frontMatterMap, from); ok && !isEmpty(value) {
+				result[key] = value
+				if from != key && !strings.Contains(from, ".") {
+					delete(result, from)
+				}
+				break
+			}
+		}
+
+		value, ok := result[key]
+		if !ok || len(field.Transforms) == 0 {
+			continue
+		}
+		transformed, err := transformValue(field.Transforms, value, key == "tags")
+		if err != nil {
+			return nil, fmt.Errorf("could not transform %s: %w", key, err)
+		}
+		result[key] = transformed
+	}
+	return result, nil
+}
+
+// transformValue transforms a string, or every item of a list. Comma separated
+// strings are only transformed item by item if commaList is set, as for tags.
+func transformValue(transforms []string, value any, commaList bool) (any, error) {
+	switch v := value.(type) {
+	case string:
+		if !commaList || !strings.Contains(v, ",") {
+			return applyTransforms(transforms, v)
+		}
+		items := strings.Split(v, ",")
+		for i, item := range items {
+			var err error
+			if items[i], err = applyTransforms(transforms, strings.TrimSpace(item)); err != nil {
+				return nil, err
+			}
+		}
+		return strings.Join(items, ", "), nil
+	case []any:
+		items := make([]any, 0, len(v))
+		for _, item := range v {
+			transformed, err := transformValue(transforms, item, false)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, transformed)
+		}
+		return items, nil
+	}
+	return value, nil
+}
+
+func applyTransforms(transforms []string, value string) (string, error) {
+	for _, transform := range transforms {
+		var err error
+		if value, err = applyTransform(transform, value); err != nil {
+			return "", err
+		}
+	}
+	return value, nil
+}
+
+// applyTransform applies one transform to a string value:
+//
+//	lowercase, uppercase, trim
+//	strip:<chars>    removes the characters from both ends, e.g. strip:# for tags
+//	prefix:<prefix>  prepends prefix to relative paths, e.g. a CDN base for covers
+//	suffix:<suffix>  appends suffix
+func applyTransform(transform, value string) (string, error) {
+	name, arg, _ := strings.Cut(transform, ":")
+	switch name {
+	case "lowercase":
+		return strings.ToLower(value), nil
+	case "uppercase":
+		return strings.ToUpper(value), nil
+	case "trim":
+		return strings.TrimSpace(value), nil
+	case "strip":
+		return strings.Trim(value, arg), nil
+	case "prefix":
+		if value == "" || strings.Contains(value, "://") || strings.HasPrefix(value, arg) {
+			return value, nil
+		}
+		return strings.TrimSuffix(arg, "/") + "/" + strings.TrimPrefix(value, "/"), nil
+	case "suffix":
+		if value == "" {
+			return value, nil
+		}
+		return value + arg, nil
+	}
+	return "", fmt.Errorf("unknown transform: %s", transform)
+}
+
+// lookupPath finds a key, or a dotted path into nested maps such as params.cover.image.
+func lookupPath(m map[string]any, path string) (any, bool) {
+	if value, ok := m[path]; ok {
+		return value, true
+	}
+	var current any = m
+	for _, part := range strings.Split(path, ".") {
+		next, ok := toStringMap(current)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = next[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func isEmpty(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []any:
+		return len(v) == 0
+	}
+	return false
+}
+
+// toStringMap accepts the map types produced by the YAML, TOML and JSON decoders.
+func toStringMap(value any) (map[string]any, bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		return v, true
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(key)] = item
+		}
+		return m, true
+	case map[string]string:
+		m := make(map[string]any, len(v))
+		for key, item := range v {
+			m[key] = item
+		}
+		return m, true
+	}
+	return nil, false
+}
+
+func toStrings(values []any) []string {
+	strs := make([]string, 0, len(values))
+	for _, value := range values {
+		strs = append(strs, fmt.Sprint(value))
+	}
+	return strs
+}
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFrontMatterMappingApply(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping map[string]any
+		input   map[string]any
+		want    map[string]any
+	}{
+		{
+			name:    "rename",
+			mapping: map[string]any{"cover_image_url": "featureImage"},
+			input:   map[string]any{"featureImage": "a.png", "title": "Hello"},
+			want:    map[string]any{"cover_image_url": "a.png", "title": "Hello"},
+		},
+		{
+			name:    "fallbacks",
+			mapping: map[string]any{"summary": []any{"description", "excerpt"}},
+			input:   map[string]any{"description": " ", "excerpt": "short"},
+			want:    map[string]any{"summary": "short", "description": " "},
+		},
+		{
+			name:    "standard key without mapped keys",
+			mapping: map[string]any{"summary": []any{"description"}},
+			input:   map[string]any{"summary": "kept"},
+			want:    map[string]any{"summary": "kept"},
+		},
+		{
+			name:    "dotted path",
+			mapping: map[string]any{"cover_image_url": map[string]any{"from": "params.cover.image"}},
+			input:   map[string]any{"params": map[string]any{"cover": map[any]any{"image": "c.png"}}},
+			want:    map[string]any{"cover_image_url": "c.png", "params": map[string]any{"cover": map[any]any{"image": "c.png"}}},
+		},
+		{
+			name:    "prefix",
+			mapping: map[string]any{"cover_image_url": map[string]any{"transforms": []any{"prefix:https://cdn.example.com/"}}},
+			input:   map[string]any{"cover_image_url": "/img/a.png"},
+			want:    map[string]any{"cover_image_url": "https://cdn.example.com/img/a.png"},
+		},
+		{
+			name:    "prefix keeps absolute urls",
+			mapping: map[string]any{"cover_image_url": map[string]any{"transforms": []any{"prefix:https://cdn.example.com/"}}},
+			input:   map[string]any{"cover_image_url": "https://example.com/a.png"},
+			want:    map[string]any{"cover_image_url": "https://example.com/a.png"},
+		},
+		{
+			name:    "comma separated tags",
+			mapping: map[string]any{"tags": map[string]any{"transforms": []any{"strip:#", "lowercase"}}},
+			input:   map[string]any{"tags": "#Go, #CLI,#quail"},
+			want:    map[string]any{"tags": "go, cli, quail"},
+		},
+		{
+			name:    "tag list",
+			mapping: map[string]any{"tags": map[string]any{"transforms": []any{"strip:#", "uppercase"}}},
+			input:   map[string]any{"tags": []any{"#go", "cli"}},
+			want:    map[string]any{"tags": []any{"GO", "CLI"}},
+		},
+		{
+			name:    "suffix",
+			mapping: map[string]any{"title": map[string]any{"transforms": []any{"trim", "suffix:!"}}},
+			input:   map[string]any{"title": " Hello "},
+			want:    map[string]any{"title": "Hello!"},
+		},
+		{
+			name:    "title with a comma",
+			mapping: map[string]any{"title": map[string]any{"transforms": []any{"suffix: | Blog"}}},
+			input:   map[string]any{"title": "Hello, world"},
+			want:    map[string]any{"title": "Hello, world | Blog"},
+		},
+		{
+			name:    "summary with commas",
+			mapping: map[string]any{"summary": map[string]any{"from": "description", "transforms": []any{"trim"}}},
+			input:   map[string]any{"description": " One,two , three "},
+			want:    map[string]any{"summary": "One,two , three"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mapping, err := ParseFrontMatterMapping(tt.mapping)
+			if err != nil {
+				t.Fatalf("ParseFrontMatterMapping() error = %v", err)
+			}
+			got, err := mapping.Apply(tt.input)
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFrontMatterMappingInvalidTransform(t *testing.T) {
+	_, err := ParseFrontMatterMapping(map[string]any{"tags": map[string]any{"transforms": []any{"reverse"}}})
+	if err == nil {
+		t.Error("ParseFrontMatterMapping() with an unknown transform did not fail")
+	}
+}
+
+func TestFrontMatterMappingName(t *testing.T) {
+	mapping, err := ParseFrontMatterMapping(map[string]any{
+		"summary":         []any{"params.description", "description"},
+		"cover_image_url": map[string]any{"from": "params.cover"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{"summary": "description", "cover_image_url": "cover_image_url", "title": "title"} {
+		if got := mapping.Name(key); got != want {
+			t.Errorf("Name(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
@@ -16,58 +16,78 @@ type QuailPostFrontMatter struct {
 	Theme         string     `yaml:"theme"`
 	Tags          string     `yaml:"tags"`
 	Datetime      *time.Time `yaml:"datetime"`
-}
+	// RelativeDatetime is set if datetime is relative, such as "tomorrow 09:00".
+	RelativeDatetime bool `yaml:"-"`
+	// Timezone is the IANA time zone of datetimes without an offset, e.g. Asia/Shanghai.
+	Timezone     string `yaml:"timezone"`
+	CanonicalURL string `yaml:"canonical_url"`
+	Lang         string `yaml:"lang"`
+	// Template renders the body with text/template before it is sent.
+	Template bool `yaml:"template"`
+	// TOC inserts a table of contents of TOCDepth heading levels.
+	TOC      bool `yaml:"toc"`
+	TOCDepth int  `yaml:"toc_depth"`
+
+	// publication state, the matching command line flags take precedence
+	List    string `yaml:"list"`
+	Draft   bool   `yaml:"draft"`
+	Publish *bool  `yaml:"publish"`
+	Deliver bool   `yaml:"deliver"`
+	// Paid makes the whole post subscriber-only if it has no paywall marker.
+	Paid bool `yaml:"paid"`
 
-var datetimeFormats = []string{
-	// add more datetime formats here
-	time.RFC1123,
-	time.RFC3339, // 2006-01-02T15:04:05Z07:00
-	"2006-01-02 15:04:05",
-	"2006-01-02 15:04",
-	"2006-01-02",
-	"02 Jan 2006",
-	"02 Jan 2006 15:04",
-	"02 Jan 2006 15:04:05",
-	"January 2, 2006",
-	"January 2, 2006 15:04",
-	"January 2, 2006 15:04:05",
+	// ID and PublishedAt are assigned by Quail and only written back to the file.
+	ID          uint64     `yaml:"id"`
+	PublishedAt *time.Time `yaml:"published_at"`
+	// DeliveredAt is written after the post is delivered, so it is only delivered once.
+	DeliveredAt *time.Time `yaml:"delivered_at"`
 }
 
-func (q *QuailPostFrontMatter) LoadFromYAML(data string, convertMap map[string]string) error {
-	var frontMatterMap map[string]any
-	err := yaml.Unmarshal([]byte(data), &frontMatterMap)
-	if err != nil {
-		return fmt.Errorf("could not parse frontmatter: %w", err)
+// LoadFromMap loads front matter that has already been parsed from YAML, TOML or JSON.
+// Datetimes without an offset are in loc, unless the front matter sets `timezone`.
+func (q *QuailPostFrontMatter) LoadFromMap(frontMatterMap map[string]any, mapping FrontMatterMapping, loc *time.Location) error {
+	if frontMatterMap == nil {
+		frontMatterMap = map[string]any{}
 	}
 
-	// convert the frontMatterMap name to standard name by using convertMap
-	for key, value := range convertMap {
-		if val, ok := frontMatterMap[value]; ok {
-			frontMatterMap[key] = val
-			delete(frontMatterMap, value)
-		}
+	// convert the frontMatterMap name to standard name by using the mapping
+	frontMatterMap, err := mapping.Apply(frontMatterMap)
+	if err != nil {
+		return err
 	}
 
-	if err := q.ConvertMapToFrontMatter(frontMatterMap); err != nil {
+	if err := q.ConvertMapToFrontMatter(frontMatterMap, loc); err != nil {
 		return fmt.Errorf("could not convert map to front matter: %w", err)
 	}
 
 	return nil
 }
 
-func (q *QuailPostFrontMatter) ConvertMapToFrontMatter(frontMatterMap map[string]any) error {
+func (q *QuailPostFrontMatter) ConvertMapToFrontMatter(frontMatterMap map[string]any, loc *time.Location) error {
 	// handle the datetime field and tags field
-	if rawDatetime, ok := frontMatterMap["datetime"]; ok {
-		if datetimeStr, ok := rawDatetime.(string); ok {
-			parsedTime, err := parseDateTime(datetimeStr)
-			if err != nil {
-				return err
+	if timezone, ok := frontMatterMap["timezone"].(string); ok && timezone != "" {
+		var err error
+		if loc, err = LoadLocation(timezone); err != nil {
+			return err
+		}
+	}
+	relative := false
+	for _, key := range []string{"datetime", "published_at", "delivered_at"} {
+		if rawDatetime, ok := frontMatterMap[key]; ok {
+			if datetimeStr, ok := rawDatetime.(string); ok {
+				if key == "datetime" {
+					relative = IsRelativeDateTime(datetimeStr)
+				}
+				parsedTime, err := ParseDateTime(datetimeStr, loc)
+				if err != nil {
+					return err
+				}
+				frontMatterMap[key] = parsedTime
 			}
-			frontMatterMap["datetime"] = parsedTime
 		}
 	}
 	if rawTags, ok := frontMatterMap["tags"]; ok {
-		frontMatterMap["tags"] = parseTags(rawTags)
+		frontMatterMap["tags"] = ParseTags(rawTags)
 	}
 
 	// Marshal the map back into YAML
@@ -80,21 +100,13 @@ func (q *QuailPostFrontMatter) ConvertMapToFrontMatter(frontMatterMap map[string
 	if err != nil {
 		return fmt.Errorf("could not unmarshal YAML to struct: %w", err)
 	}
+	q.RelativeDatetime = relative
 
 	return nil
 }
 
-func parseDateTime(datetimeStr string) (*time.Time, error) {
-	for _, layout := range datetimeFormats {
-		parsedTime, err := time.Parse(layout, datetimeStr)
-		if err == nil {
-			return &parsedTime, nil
-		}
-	}
-	return nil, fmt.Errorf("could not parse datetime: %s", datetimeStr)
-}
-
-func parseTags(rawTags any) string {
+// ParseTags normalizes tags given as a comma separated string or a list to a comma separated string.
+func ParseTags(rawTags any) string {
 	tags := []string{}
 	switch v := rawTags.(type) {
 	case string:
@@ -111,3 +123,40 @@ func parseTags(rawTags any) string {
 	}
 	return strings.Join(tags, ",")
 }
+
+// DefaultSiteURL is the Quail site posts are published on, unless `post.site_url` is set.
+const DefaultSiteURL = "https://quail.ink"
+
+// PostURL returns the public URL of a post on Quail, e.g. https://quail.ink/list/p/post.
+func PostURL(base, list, slug string) string {
+	return fmt.Sprintf("%s/%s/p/%s", strings.TrimSuffix(base, "/"), list, slug)
+}
+
+// ParsePostURL is the reverse of PostURL. ok is false if rawURL is not a post URL under base.
+func ParsePostURL(base, rawURL string) (list, slug string, ok bool) {
+	rest, found := strings.CutPrefix(rawURL, strings.TrimSuffix(base, "/")+"/")
+	if !found {
+		return "", "", false
+	}
+	if i := strings.IndexAny(rest, "?#"); i >= 0 {
+		rest = rest[:i]
+	}
+	parts := strings.Split(strings.TrimSuffix(rest, "/"), "/")
+	if len(parts) != 3 || parts[1] != "p" || parts[0] == "" || parts[2] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[2], true
+}
+
+// ToMap is the reverse of ConvertMapToFrontMatter, it returns the fields by their standard keys.
+func (q *QuailPostFrontMatter) ToMap() (map[string]any, error) {
+	yamlData, err := yaml.Marshal(q)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal front matter: %w", err)
+	}
+	frontMatterMap := map[string]any{}
+	if err := yaml.Unmarshal(yamlData, &frontMatterMap); err != nil {
+		return nil, fmt.Errorf("could not unmarshal front matter: %w", err)
+	}
+	return frontMatterMap, nil
+}
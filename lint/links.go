@@ -0,0 +1,227 @@
+package lint
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/quail-ink/quail-cli/core"
+	"github.com/quail-ink/quail-cli/util"
+)
+
+const (
+	LinkOK          = "ok"
+	LinkBroken      = "broken"
+	LinkDraft       = "draft"
+	LinkMissing     = "missing"
+	LinkUnpublished = "unpublished"
+	LinkDenied      = "denied"
+	LinkNotAllowed  = "not-allowed"
+)
+
+type PostStatus int
+
+const (
+	PostMissing PostStatus = iota
+	PostUnpublished
+	PostPublished
+)
+
+type (
+	// LinkCheckConfig is read from `post.links` in the config file. Allow and Deny are
+	// host patterns such as *.example.com, external links are only checked if one is set.
+	LinkCheckConfig struct {
+		Allow []string `mapstructure:"allow"`
+		Deny  []string `mapstructure:"deny"`
+	}
+
+	LinkChecker struct {
+		Config LinkCheckConfig
+		// BaseURL is the Quail site, links to posts under it are looked up.
+		BaseURL string
+		// List is the list the posts are published to, "" to use their list frontmatter.
+		List string
+		// MappingFor returns the front matter mapping of a file.
+		MappingFor func(file string) (core.FrontMatterMapping, error)
+		// Location is the time zone of datetimes without an offset.
+		Location *time.Location
+		// LookupPost returns the status of a post in a list, nil to skip the lookup.
+		LookupPost func(list, slug string) (PostStatus, error)
+		// SlugFor derives the slug of a file without one from its title.
+		SlugFor func(title string) string
+	}
+
+	LinkResult struct {
+		Line        int    `json:"line"`
+		Column      int    `json:"column"`
+		Destination string `json:"destination"`
+		// Target is the post a local link resolves to, as list/slug.
+		Target  string `json:"target,omitempty"`
+		Status  string `json:"status"`
+		Message string `json:"message,omitempty"`
+	}
+
+	FileLinks struct {
+		File  string       `json:"file"`
+		Error string       `json:"error,omitempty"`
+		Links []LinkResult `json:"links"`
+	}
+)
+
+// Problems returns the links that are not ok.
+func (f FileLinks) Problems() []LinkResult {
+	problems := []LinkResult{}
+	for _, link := range f.Links {
+		if link.Status != LinkOK {
+			problems = append(problems, link)
+		}
+	}
+	return problems
+}
+
+func isMarkdown(file string) bool {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".md", ".markdown", ".mdx":
+		return true
+	}
+	return false
+}
+
+// Check extracts the links of a file and checks each of them.
+func (c *LinkChecker) Check(file string) FileLinks {
+	result := FileLinks{File: file, Links: []LinkResult{}}
+	doc, err := util.ReadDocument(file)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	list := c.List
+	if list == "" {
+		if mapping, err := c.MappingFor(file); err == nil {
+			if fm, err := doc.QuailFrontMatter(mapping, c.Location); err == nil {
+				list = fm.List
+			}
+		}
+	}
+
+	for _, link := range doc.Links() {
+		res := c.CheckLink(file, list, link)
+		res.Line, res.Column = doc.Position(link.Offset)
+		result.Links = append(result.Links, res)
+	}
+	return result
+}
+
+// CheckLink checks a link of file. list is the list file is published to, it is used
+// for links to posts without a list. Target is set for links to posts.
+func (c *LinkChecker) CheckLink(file, list string, link util.Link) LinkResult {
+	res := LinkResult{Destination: link.Destination, Status: LinkOK}
+	switch {
+	case link.IsRelative():
+		c.checkLocal(file, list, link, &res)
+	case strings.HasPrefix(link.Destination, "http://") || strings.HasPrefix(link.Destination, "https://"):
+		if linkList, slug, ok := core.ParsePostURL(c.BaseURL, link.Destination); ok {
+			res.Target = linkList + "/" + slug
+			c.checkPost(linkList, slug, &res)
+		} else {
+			c.checkExternal(link.Destination, &res)
+		}
+	}
+	return res
+}
+
+func (c *LinkChecker) checkLocal(file, list string, link util.Link, res *LinkResult) {
+	target := filepath.Join(filepath.Dir(file), filepath.FromSlash(link.Path()))
+	if _, err := os.Stat(target); err != nil {
+		res.Status, res.Message = LinkBroken, fmt.Sprintf("%s does not exist", target)
+		return
+	}
+	if link.Image || !isMarkdown(target) {
+		return
+	}
+
+	// a link to another post, it is only valid if that post is published too
+	doc, err := util.ReadDocument(target)
+	if err != nil {
+		res.Status, res.Message = LinkBroken, err.Error()
+		return
+	}
+	mapping, err := c.MappingFor(target)
+	if err != nil {
+		res.Status, res.Message = LinkBroken, err.Error()
+		return
+	}
+	fm, err := doc.QuailFrontMatter(mapping, c.Location)
+	if err != nil {
+		res.Status, res.Message = LinkBroken, err.Error()
+		return
+	}
+	if fm.Draft {
+		res.Status, res.Message = LinkDraft, fmt.Sprintf("%s is a draft", target)
+		return
+	}
+
+	slug := fm.Slug
+	if slug == "" && c.SlugFor != nil {
+		slug = c.SlugFor(fm.Title)
+	}
+	if fm.List != "" {
+		list = fm.List
+	}
+	if slug == "" || list == "" {
+		res.Status, res.Message = LinkBroken, fmt.Sprintf("%s has no slug or list", target)
+		return
+	}
+	res.Target = list + "/" + slug
+	c.checkPost(list, slug, res)
+}
+
+func (c *LinkChecker) checkPost(list, slug string, res *LinkResult) {
+	if c.LookupPost == nil {
+		return
+	}
+	status, err := c.LookupPost(list, slug)
+	if err != nil {
+		res.Status, res.Message = LinkMissing, err.Error()
+		return
+	}
+	switch status {
+	case PostMissing:
+		res.Status, res.Message = LinkMissing, fmt.Sprintf("post %s/%s does not exist or was deleted", list, slug)
+	case PostUnpublished:
+		res.Status, res.Message = LinkUnpublished, fmt.Sprintf("post %s/%s is not published", list, slug)
+	}
+}
+
+func (c *LinkChecker) checkExternal(rawURL string, res *LinkResult) {
+	if len(c.Config.Allow) == 0 && len(c.Config.Deny) == 0 {
+		return
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		res.Status, res.Message = LinkBroken, err.Error()
+		return
+	}
+	host := u.Hostname()
+	if matchHost(c.Config.Deny, host) {
+		res.Status, res.Message = LinkDenied, fmt.Sprintf("%s is on the deny list", host)
+		return
+	}
+	if len(c.Config.Allow) > 0 && !matchHost(c.Config.Allow, host) {
+		res.Status, res.Message = LinkNotAllowed, fmt.Sprintf("%s is not on the allow list", host)
+	}
+}
+
+func matchHost(patterns []string, host string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, host); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,286 @@
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/quail-ink/quail-cli/core"
+	"github.com/quail-ink/quail-cli/util"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityOff     Severity = "off"
+)
+
+const (
+	RuleParse         = "parse"
+	RuleRequiredField = "required-field"
+	RuleSlugFormat    = "slug-format"
+	RuleSlugUnique    = "slug-unique"
+	RuleTitleLength   = "title-length"
+	RuleSummaryLength = "summary-length"
+	RuleDatetime      = "datetime"
+	RuleTagCount      = "tag-count"
+	RuleImageAlt      = "image-alt"
+	RuleBrokenLink    = "broken-link"
+	RuleImageSize     = "image-size"
+)
+
+var defaultSeverities = map[string]Severity{
+	RuleParse:         SeverityError,
+	RuleRequiredField: SeverityError,
+	RuleSlugFormat:    SeverityError,
+	RuleSlugUnique:    SeverityError,
+	RuleTitleLength:   SeverityWarning,
+	RuleSummaryLength: SeverityWarning,
+	RuleDatetime:      SeverityError,
+	RuleTagCount:      SeverityWarning,
+	RuleImageAlt:      SeverityWarning,
+	RuleBrokenLink:    SeverityError,
+	RuleImageSize:     SeverityWarning,
+}
+
+type (
+	// Config is read from `post.lint` in the config file.
+	Config struct {
+		// Rules sets the severity of rules by name: error, warning or off.
+		Rules            map[string]Severity `mapstructure:"rules"`
+		RequiredFields   []string            `mapstructure:"required_fields"`
+		SlugPattern      string              `mapstructure:"slug_pattern"`
+		TitleMaxLength   int                 `mapstructure:"title_max_length"`
+		SummaryMaxLength int                 `mapstructure:"summary_max_length"`
+		MaxTags          int                 `mapstructure:"max_tags"`
+		MaxImageSizeKB   int64               `mapstructure:"max_image_size_kb"`
+		// Location is the time zone of datetimes without an offset.
+		Location *time.Location `mapstructure:"-"`
+	}
+
+	Problem struct {
+		File     string   `json:"file"`
+		Line     int      `json:"line"`
+		Column   int      `json:"column"`
+		Severity Severity `json:"severity"`
+		Rule     string   `json:"rule"`
+		Message  string   `json:"message"`
+	}
+
+	// Linter checks Markdown files. It remembers the slugs of the files it has
+	// seen, so one Linter should be used for all files that are published together.
+	Linter struct {
+		config      Config
+		slugPattern *regexp.Regexp
+		slugs       map[string]string
+	}
+)
+
+func DefaultConfig() Config {
+	return Config{
+		Rules:            map[string]Severity{},
+		RequiredFields:   []string{"title"},
+		SlugPattern:      `^[a-z0-9]+(?:-[a-z0-9]+)*$`,
+		TitleMaxLength:   100,
+		SummaryMaxLength: 400,
+		MaxTags:          10,
+		MaxImageSizeKB:   5 * 1024,
+	}
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s [%s]", p.File, p.Line, p.Column, p.Severity, p.Message, p.Rule)
+}
+
+func New(config Config) (*Linter, error) {
+	for rule, severity := range config.Rules {
+		if _, ok := defaultSeverities[rule]; !ok {
+			return nil, fmt.Errorf("unknown lint rule: %s", rule)
+		}
+		if severity != SeverityError && severity != SeverityWarning && severity != SeverityOff {
+			return nil, fmt.Errorf("invalid severity %s for lint rule %s", severity, rule)
+		}
+	}
+	slugPattern, err := regexp.Compile(config.SlugPattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid slug_pattern: %w", err)
+	}
+	return &Linter{config: config, slugPattern: slugPattern, slugs: map[string]string{}}, nil
+}
+
+// HasErrors reports whether any of the problems is an error.
+func HasErrors(problems []Problem) bool {
+	for _, p := range problems {
+		if p.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+type fileLinter struct {
+	*Linter
+	file     string
+	doc      *util.Document
+	mapping  core.FrontMatterMapping
+	problems []Problem
+}
+
+func (f *fileLinter) report(rule string, line, col int, format string, args ...any) {
+	severity, ok := f.config.Rules[rule]
+	if !ok {
+		severity = defaultSeverities[rule]
+	}
+	if severity == SeverityOff {
+		return
+	}
+	f.problems = append(f.problems, Problem{
+		File:     f.file,
+		Line:     line,
+		Column:   col,
+		Severity: severity,
+		Rule:     rule,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// reportKey reports a problem at the line of a front matter field.
+func (f *fileLinter) reportKey(rule, key string, format string, args ...any) {
+	f.report(rule, f.keyLine(f.mapping.Name(key)), 1, format, args...)
+}
+
+func (f *fileLinter) reportAt(rule string, offset int, format string, args ...any) {
+	line, col := f.doc.Position(offset)
+	f.report(rule, line, col, format, args...)
+}
+
+// keyLine finds the line of a top-level key in the front matter, or returns 1.
+func (f *fileLinter) keyLine(key string) int {
+	first := 2 // after the opening delimiter
+	if f.doc.Format == util.FrontMatterJSON {
+		first = 1
+	}
+	for i, line := range strings.Split(f.doc.RawFrontMatter, "\n") {
+		trimmed := strings.Trim(strings.TrimSpace(line), `"'`)
+		if rest, ok := strings.CutPrefix(trimmed, key); ok {
+			rest = strings.TrimLeft(rest, `"' `)
+			if strings.HasPrefix(rest, ":") || strings.HasPrefix(rest, "=") {
+				return first + i
+			}
+		}
+	}
+	return 1
+}
+
+// Lint checks one Markdown file.
+func (l *Linter) Lint(file string, mapping core.FrontMatterMapping) []Problem {
+	f := &fileLinter{Linter: l, file: file, mapping: mapping}
+
+	doc, err := util.ReadDocument(file)
+	if err != nil {
+		f.report(RuleParse, 1, 1, "%s", err)
+		return f.problems
+	}
+	f.doc = doc
+
+	fields, err := mapping.Apply(doc.FrontMatter)
+	if err != nil {
+		f.report(RuleParse, 1, 1, "%s", err)
+		return f.problems
+	}
+
+	f.checkFrontMatter(fields)
+	f.checkLinks()
+
+	sort.SliceStable(f.problems, func(i, j int) bool {
+		if f.problems[i].Line != f.problems[j].Line {
+			return f.problems[i].Line < f.problems[j].Line
+		}
+		return f.problems[i].Column < f.problems[j].Column
+	})
+	return f.problems
+}
+
+func (f *fileLinter) checkFrontMatter(fields map[string]any) {
+	for _, key := range f.config.RequiredFields {
+		if value, ok := fields[key]; !ok || strings.TrimSpace(fmt.Sprint(value)) == "" {
+			f.report(RuleRequiredField, 1, 1, "missing required field %s", f.mapping.Name(key))
+		}
+	}
+
+	if slug, ok := fields["slug"].(string); ok && slug != "" {
+		if !f.slugPattern.MatchString(slug) {
+			f.reportKey(RuleSlugFormat, "slug", "slug %q does not match %s", slug, f.config.SlugPattern)
+		}
+		if other, ok := f.slugs[slug]; ok {
+			f.reportKey(RuleSlugUnique, "slug", "slug %q is also used by %s", slug, other)
+		} else {
+			f.slugs[slug] = f.file
+		}
+	}
+
+	if title, ok := fields["title"].(string); ok && f.config.TitleMaxLength > 0 && util.Width(title) > f.config.TitleMaxLength {
+		f.reportKey(RuleTitleLength, "title", "title is %d columns long, the maximum is %d", util.Width(title), f.config.TitleMaxLength)
+	}
+	if summary, ok := fields["summary"].(string); ok && f.config.SummaryMaxLength > 0 && util.Width(summary) > f.config.SummaryMaxLength {
+		f.reportKey(RuleSummaryLength, "summary", "summary is %d columns long, the maximum is %d", util.Width(summary), f.config.SummaryMaxLength)
+	}
+
+	if tags, ok := fields["tags"]; ok && f.config.MaxTags > 0 {
+		if count := len(strings.Split(core.ParseTags(tags), ",")); count > f.config.MaxTags {
+			f.reportKey(RuleTagCount, "tags", "%d tags, the maximum is %d", count, f.config.MaxTags)
+		}
+	}
+
+	loc := f.config.Location
+	if timezone, ok := fields["timezone"].(string); ok && timezone != "" {
+		var err error
+		if loc, err = core.LoadLocation(timezone); err != nil {
+			f.reportKey(RuleDatetime, "timezone", "%s", err)
+			return
+		}
+	}
+	for _, key := range []string{"datetime", "published_at", "delivered_at"} {
+		value, ok := fields[key]
+		if !ok {
+			continue
+		}
+		switch v := value.(type) {
+		case string:
+			if _, err := core.ParseDateTime(v, loc); err != nil {
+				f.reportKey(RuleDatetime, key, "%s", err)
+			}
+		case time.Time:
+		default:
+			f.reportKey(RuleDatetime, key, "%s is not a datetime", f.mapping.Name(key))
+		}
+	}
+}
+
+func (f *fileLinter) checkLinks() {
+	dir := filepath.Dir(f.file)
+	for _, link := range f.doc.Links() {
+		if link.Image && strings.TrimSpace(link.Text) == "" {
+			f.reportAt(RuleImageAlt, link.Offset, "image %s has no alt text", link.Destination)
+		}
+		if !link.IsRelative() {
+			continue
+		}
+
+		path := filepath.Join(dir, filepath.FromSlash(link.Path()))
+		info, err := os.Stat(path)
+		if err != nil {
+			f.reportAt(RuleBrokenLink, link.Offset, "%s does not exist", link.Destination)
+			continue
+		}
+		if link.Image && f.config.MaxImageSizeKB > 0 && info.Size() > f.config.MaxImageSizeKB*1024 {
+			f.reportAt(RuleImageSize, link.Offset, "image %s is %d KB, the maximum is %d KB", link.Destination, info.Size()/1024, f.config.MaxImageSizeKB)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/quail-ink/quail-cli/core"
+	"github.com/quail-ink/quail-cli/util"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestKeyLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		key     string
+		want    int
+	}{
+		{"yaml", "---\ntitle: Hello\nslug: hello\n---\nBody.\n", "slug", 3},
+		{"yaml quoted key", "---\ntitle: Hello\n\"slug\": hello\n---\nBody.\n", "slug", 3},
+		{"yaml prefix of another key", "---\nslugs: a\nslug: hello\n---\nBody.\n", "slug", 3},
+		{"toml", "+++\ntitle = \"Hello\"\n\nslug = \"hello\"\n+++\nBody.\n", "slug", 4},
+		{"json", "{\n  \"title\": \"Hello\",\n  \"slug\": \"hello\"\n}\nBody.\n", "slug", 3},
+		{"missing key", "---\ntitle: Hello\n---\nBody.\n", "slug", 1},
+		{"no front matter", "Body.\n", "slug", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := util.ParseDocument([]byte(tt.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			f := &fileLinter{doc: doc}
+			if got := f.keyLine(tt.key); got != tt.want {
+				t.Errorf("keyLine(%q) = %d, want %d", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintSeverities(t *testing.T) {
+	dir := t.TempDir()
+	file := writeFile(t, dir, "post.md", "---\nslug: Not_A_Slug\ndatetime: someday\n---\n![](missing.png)\n")
+
+	tests := []struct {
+		name  string
+		rules map[string]Severity
+		want  map[string]Severity
+	}{
+		{
+			name: "defaults",
+			want: map[string]Severity{
+				RuleRequiredField: SeverityError,
+				RuleSlugFormat:    SeverityError,
+				RuleDatetime:      SeverityError,
+				RuleImageAlt:      SeverityWarning,
+				RuleBrokenLink:    SeverityError,
+			},
+		},
+		{
+			name:  "overridden",
+			rules: map[string]Severity{RuleSlugFormat: SeverityWarning, RuleImageAlt: SeverityError, RuleRequiredField: SeverityOff, RuleBrokenLink: SeverityOff},
+			want: map[string]Severity{
+				RuleSlugFormat: SeverityWarning,
+				RuleDatetime:   SeverityError,
+				RuleImageAlt:   SeverityError,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.Rules = tt.rules
+			linter, err := New(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := map[string]Severity{}
+			for _, p := range linter.Lint(file, core.FrontMatterMapping{}) {
+				got[p.Rule] = p.Severity
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("Lint() rules = %v, want %v", got, tt.want)
+			}
+			for rule, severity := range tt.want {
+				if got[rule] != severity {
+					t.Errorf("Lint() %s = %q, want %q", rule, got[rule], severity)
+				}
+			}
+		})
+	}
+}
+
+func TestNewInvalidRules(t *testing.T) {
+	for _, rules := range []map[string]Severity{
+		{"no-such-rule": SeverityError},
+		{RuleSlugFormat: "fatal"},
+	} {
+		config := DefaultConfig()
+		config.Rules = rules
+		if _, err := New(config); err == nil {
+			t.Errorf("New() with rules %v did not fail", rules)
+		}
+	}
+}
+
+func TestLintSlugUnique(t *testing.T) {
+	dir := t.TempDir()
+	first := writeFile(t, dir, "first.md", "---\ntitle: First\nslug: hello\n---\nBody.\n")
+	second := writeFile(t, dir, "second.md", "---\ntitle: Second\npermalink: hello\n---\nBody.\n")
+	third := writeFile(t, dir, "third.md", "---\ntitle: Third\nslug: other\n---\nBody.\n")
+	mapping, err := core.ParseFrontMatterMapping(map[string]any{"slug": []any{"permalink", "slug"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	linter, err := New(DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if problems := linter.Lint(first, mapping); len(problems) != 0 {
+		t.Fatalf("Lint(first) = %v, want no problems", problems)
+	}
+	problems := linter.Lint(second, mapping)
+	if len(problems) != 1 || problems[0].Rule != RuleSlugUnique || problems[0].Line != 3 {
+		t.Fatalf("Lint(second) = %v, want slug-unique on line 3", problems)
+	}
+	if want := `slug "hello" is also used by ` + first; problems[0].Message != want {
+		t.Errorf("Lint(second) message = %q, want %q", problems[0].Message, want)
+	}
+	if problems := linter.Lint(third, mapping); len(problems) != 0 {
+		t.Errorf("Lint(third) = %v, want no problems", problems)
+	}
+}
@@ -0,0 +1,165 @@
+package transform
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/quail-ink/quail-cli/core"
+)
+
+// DefaultExecTimeout is the time an external transformer may run if its config has no timeout.
+const DefaultExecTimeout = 30 * time.Second
+
+type (
+	// StepConfig is an external transformer in the `transformers` of a project:
+	//
+	//	transformers:
+	//	  - name: footnotes
+	//	    exec: ./scripts/footnotes.py
+	//	    args: [--style, numbered]
+	//	    timeout: 10s
+	StepConfig struct {
+		Name    string        `mapstructure:"name"`
+		Exec    string        `mapstructure:"exec"`
+		Args    []string      `mapstructure:"args"`
+		Timeout time.Duration `mapstructure:"timeout"`
+	}
+
+	// Exec is an external transformer. It is given the post as a JSON message on stdin
+	// and prints the transformed post as a JSON message on stdout. Its stderr is shown
+	// to the user.
+	Exec struct {
+		name    string
+		command string
+		args    []string
+		dir     string
+		timeout time.Duration
+		// location is the time zone of datetimes without an offset in the output
+		location *time.Location
+	}
+
+	// message is the JSON exchanged with external transformers. A transformer may
+	// leave out frontmatter and params to keep them.
+	message struct {
+		File        string         `json:"file"`
+		List        string         `json:"list"`
+		FrontMatter map[string]any `json:"frontmatter,omitempty"`
+		Params      map[string]any `json:"params,omitempty"`
+		Content     string         `json:"content"`
+	}
+)
+
+// NewExec returns the external transformer of a step. Relative commands with a
+// slash, such as ./scripts/footnotes.py, and the working directory are relative to dir.
+// Datetimes without an offset in the frontmatter it prints are in loc.
+func NewExec(config StepConfig, dir string, loc *time.Location) (*Exec, error) {
+	if config.Exec == "" {
+		return nil, fmt.Errorf("transformer %s has no exec", config.Name)
+	}
+	command := config.Exec
+	if strings.ContainsRune(command, '/') && !filepath.IsAbs(command) {
+		command = filepath.Join(dir, command)
+	}
+	name := config.Name
+	if name == "" {
+		name = filepath.Base(config.Exec)
+	}
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = DefaultExecTimeout
+	}
+	return &Exec{name: name, command: command, args: config.Args, dir: dir, timeout: timeout, location: loc}, nil
+}
+
+func (e *Exec) Name() string {
+	return e.name
+}
+
+func (e *Exec) Transform(post *Post) error {
+	frontMatter, err := post.FrontMatter.ToMap()
+	if err != nil {
+		return err
+	}
+	input, err := json.Marshal(message{
+		File:        post.File,
+		List:        post.List,
+		FrontMatter: frontMatter,
+		Params:      jsonMap(post.Params),
+		Content:     post.Content,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, e.command, e.args...)
+	cmd.Dir = e.dir
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stderr = os.Stderr
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("timed out after %s", e.timeout)
+		}
+		return err
+	}
+
+	output := message{}
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return fmt.Errorf("invalid output: %w", err)
+	}
+	if output.FrontMatter != nil {
+		transformed := &core.QuailPostFrontMatter{}
+		if err := transformed.ConvertMapToFrontMatter(output.FrontMatter, e.location); err != nil {
+			return err
+		}
+		post.FrontMatter = transformed
+	}
+	if output.Params != nil {
+		post.Params = output.Params
+	}
+	post.Content = output.Content
+	return nil
+}
+
+// jsonMap converts the map[any]any values of YAML front matter to map[string]any,
+// which can be encoded as JSON.
+func jsonMap(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]any, len(m))
+	for key, value := range m {
+		out[key] = jsonValue(value)
+	}
+	return out
+}
+
+func jsonValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return jsonMap(v)
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(key)] = item
+		}
+		return jsonMap(m)
+	case []any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = jsonValue(item)
+		}
+		return items
+	}
+	return value
+}
@@ -0,0 +1,82 @@
+// Package transform is the pipeline that changes a post between parsing its file
+// and sending it to Quail, e.g. to expand includes or rewrite links.
+package transform
+
+import (
+	"fmt"
+
+	"github.com/quail-ink/quail-cli/core"
+)
+
+type (
+	// Post is the post passed through the transformers.
+	Post struct {
+		// File is the path of the Markdown file, empty if it was read from stdin.
+		File string
+		List string
+		// FrontMatter is the front matter after the mapping and the flags are applied.
+		FrontMatter *core.QuailPostFrontMatter
+		// Params is the front matter as written in the file, including custom keys.
+		Params  map[string]any
+		Content string
+	}
+
+	Transformer interface {
+		Name() string
+		Transform(post *Post) error
+	}
+
+	// Pipeline runs transformers in order.
+	Pipeline []Transformer
+
+	funcTransformer struct {
+		name string
+		fn   func(post *Post) error
+	}
+)
+
+// Name returns the file of the post for messages, or stdin.
+func (p *Post) Name() string {
+	if p.File == "" {
+		return "stdin"
+	}
+	return p.File
+}
+
+// NewFunc returns a transformer calling fn.
+func NewFunc(name string, fn func(post *Post) error) Transformer {
+	return &funcTransformer{name: name, fn: fn}
+}
+
+func (t *funcTransformer) Name() string {
+	return t.name
+}
+
+func (t *funcTransformer) Transform(post *Post) error {
+	return t.fn(post)
+}
+
+// Run passes the post through the transformers and stops at the first error.
+func (p Pipeline) Run(post *Post) error {
+	for _, t := range p {
+		if err := t.Transform(post); err != nil {
+			return fmt.Errorf("transformer %s failed: %w", t.Name(), err)
+		}
+	}
+	return nil
+}
+
+// Without returns the pipeline without the transformers with the given names.
+func (p Pipeline) Without(names ...string) Pipeline {
+	disabled := map[string]bool{}
+	for _, name := range names {
+		disabled[name] = true
+	}
+	pipeline := Pipeline{}
+	for _, t := range p {
+		if !disabled[t.Name()] {
+			pipeline = append(pipeline, t)
+		}
+	}
+	return pipeline
+}
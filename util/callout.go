@@ -0,0 +1,140 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// CalloutFormatQuote renders callouts as blockquotes starting with the icon and
+	// the title in bold, which works everywhere.
+	CalloutFormatQuote = "quote"
+	// CalloutFormatGitHub keeps callouts as GitHub alerts, e.g. > [!WARNING].
+	CalloutFormatGitHub = "github"
+)
+
+// CalloutStyle is how a callout type is rendered.
+type CalloutStyle struct {
+	Icon  string `mapstructure:"icon"`
+	Label string `mapstructure:"label"`
+	// Alert is the GitHub alert type used by CalloutFormatGitHub, one of NOTE, TIP,
+	// IMPORTANT, WARNING and CAUTION.
+	Alert string `mapstructure:"alert"`
+}
+
+// DefaultCalloutStyles covers the callout types of Obsidian, which include the
+// alert types of GitHub, and the admonition types of Docusaurus, with their aliases.
+var DefaultCalloutStyles = map[string]CalloutStyle{
+	"note":      {Icon: "📝", Label: "Note", Alert: "NOTE"},
+	"abstract":  {Icon: "📋", Label: "Abstract", Alert: "NOTE"},
+	"summary":   {Icon: "📋", Label: "Summary", Alert: "NOTE"},
+	"tldr":      {Icon: "📋", Label: "TL;DR", Alert: "NOTE"},
+	"info":      {Icon: "ℹ️", Label: "Info", Alert: "NOTE"},
+	"todo":      {Icon: "☑️", Label: "Todo", Alert: "NOTE"},
+	"tip":       {Icon: "💡", Label: "Tip", Alert: "TIP"},
+	"hint":      {Icon: "💡", Label: "Hint", Alert: "TIP"},
+	"important": {Icon: "❗", Label: "Important", Alert: "IMPORTANT"},
+	"success":   {Icon: "✅", Label: "Success", Alert: "TIP"},
+	"check":     {Icon: "✅", Label: "Check", Alert: "TIP"},
+	"done":      {Icon: "✅", Label: "Done", Alert: "TIP"},
+	"question":  {Icon: "❓", Label: "Question", Alert: "NOTE"},
+	"help":      {Icon: "❓", Label: "Help", Alert: "NOTE"},
+	"faq":       {Icon: "❓", Label: "FAQ", Alert: "NOTE"},
+	"warning":   {Icon: "⚠️", Label: "Warning", Alert: "WARNING"},
+	"caution":   {Icon: "⚠️", Label: "Caution", Alert: "CAUTION"},
+	"attention": {Icon: "⚠️", Label: "Attention", Alert: "WARNING"},
+	"failure":   {Icon: "❌", Label: "Failure", Alert: "CAUTION"},
+	"fail":      {Icon: "❌", Label: "Fail", Alert: "CAUTION"},
+	"missing":   {Icon: "❌", Label: "Missing", Alert: "CAUTION"},
+	"danger":    {Icon: "⚡", Label: "Danger", Alert: "CAUTION"},
+	"error":     {Icon: "⚡", Label: "Error", Alert: "CAUTION"},
+	"bug":       {Icon: "🐞", Label: "Bug", Alert: "WARNING"},
+	"example":   {Icon: "📑", Label: "Example", Alert: "NOTE"},
+	"quote":     {Icon: "💬", Label: "Quote", Alert: "NOTE"},
+	"cite":      {Icon: "💬", Label: "Quote", Alert: "NOTE"},
+}
+
+var (
+	// calloutRe matches the first line of a callout, e.g. `> [!warning]- Custom title`.
+	// Callouts can be nested in blockquotes and other callouts.
+	calloutRe = regexp.MustCompile(`(?m)^((?:>[ \t]?)+)\[!([A-Za-z-]+)\][+-]?[ \t]*(.*)$`)
+	// admonitionRe matches the opening line of an admonition, e.g. `:::warning[Custom title]`
+	// or `::: warning Custom title`, and the closing line `:::`.
+	admonitionRe = regexp.MustCompile(`^[ \t]*(:{3,})[ \t]*([A-Za-z-]*)[ \t]*(?:\[(.*)\]|(.*?))[ \t]*$`)
+)
+
+// ConvertCallouts renders Obsidian callouts and GitHub alerts in the format, with
+// the style of their type. Types without a style use their name as the label.
+func ConvertCallouts(body string, styles map[string]CalloutStyle, format string) string {
+	return ReplaceOutsideCode(body, calloutRe, func(match []string) string {
+		prefix := strings.TrimRight(match[1], " \t")
+		kind, title := match[2], strings.TrimSpace(match[3])
+		style, ok := styles[strings.ToLower(kind)]
+		if !ok {
+			style = CalloutStyle{Label: strings.ToUpper(kind[:1]) + strings.ToLower(kind[1:]), Alert: "NOTE"}
+		}
+		if format == CalloutFormatGitHub {
+			line := prefix + " [!" + style.Alert + "]"
+			if title != "" && title != style.Label {
+				// GitHub alerts have no titles, keep it as the first line
+				line += "\n" + prefix + " **" + title + "**\n" + prefix
+			}
+			return line
+		}
+
+		if title == "" {
+			title = style.Label
+		}
+		if style.Icon != "" {
+			title = style.Icon + " " + title
+		}
+		// the title is a paragraph of its own
+		return prefix + " **" + title + "**\n" + prefix
+	})
+}
+
+// ConvertAdmonitions rewrites admonitions, as in Docusaurus and VuePress, to GitHub alerts:
+//
+//	:::warning[Custom title]
+//	Content
+//	:::
+func ConvertAdmonitions(body string) string {
+	ranges := codeRanges(body)
+	lines := strings.SplitAfter(body, "\n")
+	var b strings.Builder
+	offset := 0
+	// the number of colons of the open admonitions
+	stack := []int{}
+	for _, line := range lines {
+		start := offset
+		offset += len(line)
+		prefix := strings.Repeat("> ", len(stack))
+		match := admonitionRe.FindStringSubmatch(strings.TrimRight(line, "\r\n"))
+		if match == nil || inRanges(ranges, start) {
+			if strings.TrimSpace(line) == "" && len(stack) > 0 {
+				b.WriteString(strings.TrimRight(prefix, " ") + line)
+			} else {
+				b.WriteString(prefix + line)
+			}
+			continue
+		}
+
+		colons, kind := len(match[1]), match[2]
+		title := strings.TrimSpace(match[3] + match[4])
+		if kind == "" && title == "" && len(stack) > 0 && colons >= stack[len(stack)-1] {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		if kind == "" {
+			b.WriteString(prefix + line)
+			continue
+		}
+		header := prefix + "> [!" + kind + "]"
+		if title != "" {
+			header += " " + title
+		}
+		b.WriteString(header + "\n")
+		stack = append(stack, colons)
+	}
+	return b.String()
+}
@@ -0,0 +1,94 @@
+package util
+
+import "testing"
+
+func TestConvertCallouts(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		quote  string
+		github string
+	}{
+		{
+			name:   "github alert",
+			body:   "> [!WARNING]\n> Careful.\n",
+			quote:  "> **⚠️ Warning**\n>\n> Careful.\n",
+			github: "> [!WARNING]\n> Careful.\n",
+		},
+		{
+			name:   "obsidian callout with title",
+			body:   "> [!tip]- Custom\n> Text\n",
+			quote:  "> **💡 Custom**\n>\n> Text\n",
+			github: "> [!TIP]\n> **Custom**\n>\n> Text\n",
+		},
+		{
+			name:   "unknown type",
+			body:   "> [!custom] \n> x\n",
+			quote:  "> **Custom**\n>\n> x\n",
+			github: "> [!NOTE]\n> x\n",
+		},
+		{
+			name:   "nested",
+			body:   "> > [!note]\n> > nested\n",
+			quote:  "> > **📝 Note**\n> >\n> > nested\n",
+			github: "> > [!NOTE]\n> > nested\n",
+		},
+		{
+			name:   "code",
+			body:   "```\n> [!note]\n```\n",
+			quote:  "```\n> [!note]\n```\n",
+			github: "```\n> [!note]\n```\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConvertCallouts(tt.body, DefaultCalloutStyles, CalloutFormatQuote); got != tt.quote {
+				t.Errorf("ConvertCallouts(%q, quote) = %q, want %q", tt.body, got, tt.quote)
+			}
+			if got := ConvertCallouts(tt.body, DefaultCalloutStyles, CalloutFormatGitHub); got != tt.github {
+				t.Errorf("ConvertCallouts(%q, github) = %q, want %q", tt.body, got, tt.github)
+			}
+		})
+	}
+}
+
+func TestConvertAdmonitions(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "bracket title",
+			body: ":::warning[Custom title]\nContent\n:::\n",
+			want: "> [!warning] Custom title\n> Content\n",
+		},
+		{
+			name: "blank lines",
+			body: "::: tip\nOne\n\nTwo\n:::\n",
+			want: "> [!tip]\n> One\n>\n> Two\n",
+		},
+		{
+			name: "nested",
+			body: "::::note\nOuter\n:::tip\nInner\n:::\n::::\n",
+			want: "> [!note]\n> Outer\n> > [!tip]\n> > Inner\n",
+		},
+		{
+			name: "code",
+			body: "```\n:::warning\n```\n",
+			want: "```\n:::warning\n```\n",
+		},
+		{
+			name: "unopened closing line",
+			body: ":::\nplain\n",
+			want: ":::\nplain\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConvertAdmonitions(tt.body); got != tt.want {
+				t.Errorf("ConvertAdmonitions(%q) = %q, want %q", tt.body, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package util
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// cjk are the characters of Chinese and Japanese text. Korean uses spaces between
+// words and is left alone.
+const cjk = `\p{Han}\p{Hiragana}\p{Katakana}ー`
+
+var (
+	cjkRe                = regexp.MustCompile(`[` + cjk + `]`)
+	cjkBeforeLatinRe     = regexp.MustCompile(`([` + cjk + `])([A-Za-z0-9@#$&])`)
+	cjkAfterLatinRe      = regexp.MustCompile(`([A-Za-z0-9%])([` + cjk + `])`)
+	cjkPunctuationRe     = regexp.MustCompile(`([` + cjk + `])[ \t]*([,!?:;])[ \t]*`)
+	cjkPeriodRe          = regexp.MustCompile(`(?m)([` + cjk + `])\.([ \t]+|$|[` + cjk + `])`)
+	cjkParenthesesRe     = regexp.MustCompile(`\(([^()\n]*[` + cjk + `][^()\n]*)\)`)
+	cjkQuotesRe          = regexp.MustCompile(`["“”]([^"“”\n]*[` + cjk + `][^"“”\n]*)["“”]`)
+	cjkSpaceBeforeRe     = regexp.MustCompile(`([` + cjk + `])[ \t]+([，。！？：；、）」』”])`)
+	cjkSpaceAfterRe      = regexp.MustCompile(`([，。！？：；、（「『“])[ \t]+`)
+	fullWidthAlnumRe     = regexp.MustCompile(`[０-９Ａ-Ｚａ-ｚ]+`)
+	bareURLRe            = regexp.MustCompile(`https?://[^\s<>()\[\]，。！？；：、“”「」]+`)
+	fullWidthPunctuation = map[string]string{",": "，", "!": "！", "?": "？", ":": "：", ";": "；"}
+)
+
+// protected is a part of a body NormalizeCJK must not change.
+type protected struct {
+	start, end int
+	// spaced parts, such as code spans and URLs, are separated from CJK text by spaces
+	spaced bool
+}
+
+// NormalizeCJK applies the usual typography of Chinese and Japanese text to a Markdown
+// body: spaces between CJK and Latin characters or numbers, full-width punctuation
+// after CJK characters, half-width letters and numbers instead of full-width ones
+// next to CJK characters and curly quotes around CJK text. Text without CJK
+// characters is not changed. Code, URLs, link destinations and HTML tags are not changed.
+func NormalizeCJK(body string) string {
+	parts := []protected{}
+	for _, r := range codeRanges(body) {
+		parts = append(parts, protected{r[0], r[1], true})
+	}
+	for _, loc := range bareURLRe.FindAllStringIndex(body, -1) {
+		parts = append(parts, protected{loc[0], loc[1], true})
+	}
+	for _, loc := range htmlTagRe.FindAllStringIndex(body, -1) {
+		parts = append(parts, protected{loc[0], loc[1], false})
+	}
+	doc := &Document{Body: []byte(body)}
+	for _, link := range doc.Links() {
+		end := link.Offset + len(link.Destination)
+		if link.Destination != "" && end <= len(body) && body[link.Offset:end] == link.Destination {
+			parts = append(parts, protected{link.Offset, end, false})
+		}
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].start < parts[j].start })
+
+	var b strings.Builder
+	last := 0
+	for _, part := range parts {
+		if part.start < last {
+			// inside a part that is already protected
+			continue
+		}
+		text := normalizeCJKText(body[last:part.start])
+		if part.spaced && cjkRe.MatchString(lastRune(text)) {
+			text += " "
+		}
+		b.WriteString(text)
+		b.WriteString(body[part.start:part.end])
+		last = part.end
+		if part.spaced && last < len(body) && cjkRe.MatchString(firstRune(body[last:])) {
+			b.WriteString(" ")
+		}
+	}
+	b.WriteString(normalizeCJKText(body[last:]))
+	return b.String()
+}
+
+func normalizeCJKText(s string) string {
+	if !cjkRe.MatchString(s) {
+		return s
+	}
+	s = toHalfWidth(s)
+	s = cjkQuotesRe.ReplaceAllString(s, "“$1”")
+	s = cjkParenthesesRe.ReplaceAllString(s, "（$1）")
+	s = toFullWidthPunctuation(s)
+	s = cjkPeriodRe.ReplaceAllStringFunc(s, func(match string) string {
+		m := cjkPeriodRe.FindStringSubmatch(match)
+		if strings.TrimSpace(m[2]) == "" {
+			return m[1] + "。"
+		}
+		return m[1] + "。" + m[2]
+	})
+	s = cjkSpaceBeforeRe.ReplaceAllString(s, "$1$2")
+	s = cjkSpaceAfterRe.ReplaceAllString(s, "$1")
+	// twice, as a match consumes the character the next match would start with
+	for i := 0; i < 2; i++ {
+		s = cjkBeforeLatinRe.ReplaceAllString(s, "$1 $2")
+		s = cjkAfterLatinRe.ReplaceAllString(s, "$1 $2")
+	}
+	return s
+}
+
+// toFullWidthPunctuation replaces half-width punctuation after CJK characters with
+// full-width punctuation. The ! of an image, as in ![alt](src), is kept.
+func toFullWidthPunctuation(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range cjkPunctuationRe.FindAllStringSubmatchIndex(s, -1) {
+		punctuation := s[m[4]:m[5]]
+		if punctuation == "!" && strings.HasPrefix(s[m[5]:], "[") {
+			continue
+		}
+		b.WriteString(s[last:m[0]])
+		b.WriteString(s[m[2]:m[3]])
+		b.WriteString(fullWidthPunctuation[punctuation])
+		last = m[1]
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// toHalfWidth replaces full-width letters and numbers, such as Ａ and １, next to CJK
+// characters with ASCII ones. Other full-width text may be deliberate and is kept.
+func toHalfWidth(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range fullWidthAlnumRe.FindAllStringIndex(s, -1) {
+		before := lastRune(strings.TrimRight(s[:loc[0]], " \t"))
+		after := firstRune(strings.TrimLeft(s[loc[1]:], " \t"))
+		if !cjkRe.MatchString(before) && !cjkRe.MatchString(after) {
+			continue
+		}
+		b.WriteString(s[last:loc[0]])
+		b.WriteString(strings.Map(func(r rune) rune { return r - 0xFEE0 }, s[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+func firstRune(s string) string {
+	for _, r := range s {
+		return string(r)
+	}
+	return ""
+}
+
+func lastRune(s string) string {
+	if s == "" {
+		return ""
+	}
+	runes := []rune(s)
+	return string(runes[len(runes)-1])
+}
@@ -0,0 +1,36 @@
+package util
+
+import "testing"
+
+func TestNormalizeCJK(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"spacing", "中文English混排\n", "中文 English 混排\n"},
+		{"numbers", "Version 2中文\n", "Version 2 中文\n"},
+		{"punctuation", "测试,好的!\n", "测试，好的！\n"},
+		{"period", "结束.\n", "结束。\n"},
+		{"quotes and parentheses", "他说\"你好\"然后(离开)了.\n", "他说“你好”然后（离开）了。\n"},
+		{"full width next to cjk", "我有１００个ＧＰＵ\n", "我有 100 个 GPU\n"},
+		{"full width with space", "你好 ＡＢＣ\n", "你好 ABC\n"},
+		{"full width without cjk", "ＡＢＣ only full width\n", "ＡＢＣ only full width\n"},
+		{"full width away from cjk", "你好\n\nＸＹＺ here\n", "你好\n\nＸＹＺ here\n"},
+		{"latin text", "No CJK here, really.\n", "No CJK here, really.\n"},
+		{"code span", "见 `code中文` 了\n", "见 `code中文` 了\n"},
+		{"code block", "```\n中文English\n```\n", "```\n中文English\n```\n"},
+		{"link destination", "[链接](https://example.com/a,b)很好\n", "[链接](https://example.com/a,b)很好\n"},
+		{"bare url", "访问https://example.com/路径\n", "访问 https://example.com/路径\n"},
+		{"image", "看图![图片](a.png)\n", "看图![图片](a.png)\n"},
+		{"image after punctuation", "好的!![图片](a.png)\n", "好的！![图片](a.png)\n"},
+		{"html tag", "<span title=\"a,b\">中文</span>\n", "<span title=\"a,b\">中文</span>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeCJK(tt.body); got != tt.want {
+				t.Errorf("NormalizeCJK(%q) = %q, want %q", tt.body, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package util
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// codeRanges returns the byte ranges of the code blocks, including their fences,
+// and code spans of a Markdown body, which must not be rewritten.
+func codeRanges(body string) [][2]int {
+	source := []byte(body)
+	root := markdownParser.Parse(text.NewReader(source))
+	ranges := [][2]int{}
+	ast.Walk(root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.FencedCodeBlock:
+			if node.Lines().Len() == 0 {
+				return ast.WalkSkipChildren, nil
+			}
+			start, end := blockRange(node)
+			// widen to the opening and closing fences
+			if start > 0 {
+				start = strings.LastIndexByte(body[:start-1], '\n') + 1
+			}
+			if i := strings.IndexByte(body[end:], '\n'); i >= 0 && end < len(body) {
+				end += i
+			} else {
+				end = len(body)
+			}
+			ranges = append(ranges, [2]int{start, end})
+			return ast.WalkSkipChildren, nil
+		case *ast.CodeBlock:
+			start, end := blockRange(node)
+			ranges = append(ranges, [2]int{start, end})
+			return ast.WalkSkipChildren, nil
+		case *ast.CodeSpan:
+			start, end := -1, 0
+			for c := node.FirstChild(); c != nil; c = c.NextSibling() {
+				if t, ok := c.(*ast.Text); ok {
+					if start < 0 {
+						start = t.Segment.Start
+					}
+					end = t.Segment.Stop
+				}
+			}
+			if start >= 0 {
+				// include the backticks
+				for start > 0 && body[start-1] == '`' {
+					start--
+				}
+				for end < len(body) && body[end] == '`' {
+					end++
+				}
+				ranges = append(ranges, [2]int{start, end})
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+	return ranges
+}
+
+// blockRange returns the range of the lines of a block node.
+func blockRange(n ast.Node) (int, int) {
+	lines := n.Lines()
+	if lines.Len() == 0 {
+		return 0, 0
+	}
+	return lines.At(0).Start, lines.At(lines.Len() - 1).Stop
+}
+
+// inRanges reports whether offset is inside one of the sorted ranges.
+func inRanges(ranges [][2]int, offset int) bool {
+	i := sort.Search(len(ranges), func(i int) bool { return ranges[i][1] > offset })
+	return i < len(ranges) && ranges[i][0] <= offset
+}
+
+// ReplaceOutsideCode is like regexp.ReplaceAllStringFunc with the submatches of
+// each match, but leaves matches starting in code blocks and code spans unchanged.
+func ReplaceOutsideCode(body string, re *regexp.Regexp, fn func(match []string) string) string {
+	ranges := codeRanges(body)
+	var b strings.Builder
+	last := 0
+	for _, loc := range re.FindAllStringSubmatchIndex(body, -1) {
+		if inRanges(ranges, loc[0]) {
+			continue
+		}
+		match := make([]string, len(loc)/2)
+		for i := range match {
+			if loc[2*i] >= 0 {
+				match[i] = body[loc[2*i]:loc[2*i+1]]
+			}
+		}
+		b.WriteString(body[last:loc[0]])
+		b.WriteString(fn(match))
+		last = loc[1]
+	}
+	b.WriteString(body[last:])
+	return b.String()
+}
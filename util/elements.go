@@ -0,0 +1,109 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// element is a tag of a Hugo shortcode or an MDX component found in a body.
+type element struct {
+	start, end  int
+	raw         string
+	name        string
+	args        map[string]string
+	positional  []string
+	closing     bool
+	selfClosing bool
+}
+
+// arg returns the named argument, or else the positional argument at index i.
+func (e element) arg(name string, i int) string {
+	if v, ok := e.args[name]; ok {
+		return v
+	}
+	if i >= 0 && i < len(e.positional) {
+		return e.positional[i]
+	}
+	return ""
+}
+
+// convertElements replaces the elements found in body, with the converted inner
+// content of paired elements. An opening tag without a closing tag is converted
+// on its own, a stray closing tag is removed.
+func convertElements(body string, find func(string) []element, convert func(e element, inner string, paired bool) string) string {
+	tags := find(body)
+	var b strings.Builder
+	last := 0
+	for i := 0; i < len(tags); i++ {
+		tag := tags[i]
+		b.WriteString(body[last:tag.start])
+		last = tag.end
+		if tag.closing {
+			continue
+		}
+		if !tag.selfClosing {
+			if j := matchingTag(tags, i); j >= 0 {
+				inner := convertElements(body[tag.end:tags[j].start], find, convert)
+				b.WriteString(convert(tag, inner, true))
+				last = tags[j].end
+				i = j
+				continue
+			}
+		}
+		b.WriteString(convert(tag, "", false))
+	}
+	b.WriteString(body[last:])
+	return b.String()
+}
+
+// matchingTag returns the index of the closing tag of tags[i], or -1.
+func matchingTag(tags []element, i int) int {
+	depth := 0
+	for j := i + 1; j < len(tags); j++ {
+		if tags[j].name != tags[i].name || tags[j].selfClosing {
+			continue
+		}
+		if !tags[j].closing {
+			depth++
+		} else if depth == 0 {
+			return j
+		} else {
+			depth--
+		}
+	}
+	return -1
+}
+
+// formatFigure renders an image with an optional link and caption.
+func formatFigure(src, alt, title, caption, link string) string {
+	if title != "" {
+		src += fmt.Sprintf(" %q", title)
+	}
+	image := fmt.Sprintf("![%s](%s)", alt, src)
+	if link != "" {
+		image = fmt.Sprintf("[%s](%s)", image, link)
+	}
+	if caption != "" {
+		image += "\n\n*" + caption + "*"
+	}
+	return image
+}
+
+// formatCallout renders a callout as a GitHub alert, which ConvertCallouts renders
+// in the configured format.
+func formatCallout(kind, title, inner string) string {
+	lines := []string{strings.TrimRight("> [!"+kind+"] "+title, " ")}
+	for _, line := range strings.Split(strings.Trim(inner, "\n"), "\n") {
+		lines = append(lines, strings.TrimRight("> "+line, " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatDetails renders a collapsible section as its bold summary followed by its content.
+func formatDetails(summary, inner string) string {
+	inner = strings.Trim(inner, "\n")
+	if summary == "" {
+		return inner
+	}
+	return "**" + summary + "**\n\n" + inner
+}
@@ -0,0 +1,168 @@
+package util
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type FeedEntry struct {
+	GUID        string
+	Title       string
+	Link        string
+	Summary     string
+	Content     string
+	Tags        []string
+	PublishedAt *time.Time
+}
+
+type (
+	rssFeed struct {
+		Channel struct {
+			Items []rssItem `xml:"item"`
+		} `xml:"channel"`
+	}
+	rssItem struct {
+		GUID           string   `xml:"guid"`
+		Title          string   `xml:"title"`
+		Link           string   `xml:"link"`
+		Description    string   `xml:"description"`
+		ContentEncoded string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+		Categories     []string `xml:"category"`
+		PubDate        string   `xml:"pubDate"`
+	}
+	atomFeed struct {
+		Entries []atomEntry `xml:"entry"`
+	}
+	atomEntry struct {
+		ID    string `xml:"id"`
+		Title string `xml:"title"`
+		Links []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Summary    string `xml:"summary"`
+		Content    string `xml:"content"`
+		Categories []struct {
+			Term string `xml:"term,attr"`
+		} `xml:"category"`
+		Published string `xml:"published"`
+		Updated   string `xml:"updated"`
+	}
+)
+
+var feedDatetimeFormats = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC3339,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+}
+
+// ParseFeed reads an RSS 2.0 or Atom document and returns its entries in document order.
+func ParseFeed(r io.Reader) ([]FeedEntry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("could not read feed: %w", err)
+	}
+
+	root := struct {
+		XMLName xml.Name
+	}{}
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("could not parse feed: %w", err)
+	}
+
+	switch root.XMLName.Local {
+	case "rss":
+		return parseRSS(data)
+	case "feed":
+		return parseAtom(data)
+	}
+	return nil, fmt.Errorf("unsupported feed format: <%s>", root.XMLName.Local)
+}
+
+func parseRSS(data []byte) ([]FeedEntry, error) {
+	feed := &rssFeed{}
+	if err := xml.Unmarshal(data, feed); err != nil {
+		return nil, fmt.Errorf("could not parse rss feed: %w", err)
+	}
+
+	entries := make([]FeedEntry, 0, len(feed.Channel.Items))
+	for _, item := range feed.Channel.Items {
+		entry := FeedEntry{
+			GUID:        strings.TrimSpace(item.GUID),
+			Title:       strings.TrimSpace(item.Title),
+			Link:        strings.TrimSpace(item.Link),
+			Summary:     item.Description,
+			Content:     item.ContentEncoded,
+			PublishedAt: parseFeedDatetime(item.PubDate),
+		}
+		if entry.Content == "" {
+			entry.Content = item.Description
+			entry.Summary = ""
+		}
+		if entry.GUID == "" {
+			entry.GUID = entry.Link
+		}
+		for _, category := range item.Categories {
+			entry.Tags = append(entry.Tags, strings.TrimSpace(category))
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func parseAtom(data []byte) ([]FeedEntry, error) {
+	feed := &atomFeed{}
+	if err := xml.Unmarshal(data, feed); err != nil {
+		return nil, fmt.Errorf("could not parse atom feed: %w", err)
+	}
+
+	entries := make([]FeedEntry, 0, len(feed.Entries))
+	for _, item := range feed.Entries {
+		entry := FeedEntry{
+			GUID:    strings.TrimSpace(item.ID),
+			Title:   strings.TrimSpace(item.Title),
+			Summary: item.Summary,
+			Content: item.Content,
+		}
+		for _, link := range item.Links {
+			if link.Rel == "" || link.Rel == "alternate" {
+				entry.Link = strings.TrimSpace(link.Href)
+				break
+			}
+		}
+		if entry.Content == "" {
+			entry.Content = item.Summary
+			entry.Summary = ""
+		}
+		if entry.GUID == "" {
+			entry.GUID = entry.Link
+		}
+		for _, category := range item.Categories {
+			entry.Tags = append(entry.Tags, strings.TrimSpace(category.Term))
+		}
+		entry.PublishedAt = parseFeedDatetime(item.Published)
+		if entry.PublishedAt == nil {
+			entry.PublishedAt = parseFeedDatetime(item.Updated)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func parseFeedDatetime(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	for _, layout := range feedDatetimeFormats {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
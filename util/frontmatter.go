@@ -0,0 +1,389 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	toml "github.com/pelletier/go-toml/v2"
+	yaml "gopkg.in/yaml.v2"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+type FrontMatterFormat string
+
+const bom = "\uFEFF"
+
+const (
+	FrontMatterNone FrontMatterFormat = ""
+	FrontMatterYAML FrontMatterFormat = "yaml" // --- delimited
+	FrontMatterTOML FrontMatterFormat = "toml" // +++ delimited
+	FrontMatterJSON FrontMatterFormat = "json" // a leading { ... } object
+)
+
+// SplitFrontMatter detects the front matter block at the start of data and splits
+// it from the body. raw is the front matter without its delimiters, except for
+// JSON where the braces are part of the object.
+//
+// Only a delimiter on the very first line (after an optional BOM) opens a front
+// matter block, so thematic breaks in the body are left alone. A `---` that is
+// never closed is a thematic break, not front matter. Line endings may be LF or CRLF.
+func SplitFrontMatter(data string) (format FrontMatterFormat, raw, body string, err error) {
+	data = strings.TrimPrefix(data, bom)
+
+	first, rest, found := strings.Cut(data, "\n")
+	switch strings.TrimRight(first, " \t\r") {
+	case "---":
+		format = FrontMatterYAML
+	case "+++":
+		format = FrontMatterTOML
+	case "{":
+		return splitJSONFrontMatter(data)
+	default:
+		if strings.HasPrefix(first, `{"`) {
+			return splitJSONFrontMatter(data)
+		}
+		return FrontMatterNone, "", data, nil
+	}
+	if !found {
+		return FrontMatterNone, "", data, nil
+	}
+
+	delimiter := strings.TrimSpace(first)
+	offset := 0
+	for _, line := range strings.SplitAfter(rest, "\n") {
+		if strings.TrimRight(line, " \t\r\n") == delimiter {
+			return format, rest[:offset], rest[offset+len(line):], nil
+		}
+		offset += len(line)
+	}
+	if format == FrontMatterYAML {
+		return FrontMatterNone, "", data, nil
+	}
+	return FrontMatterNone, "", "", fmt.Errorf("frontmatter is not closed with %s", delimiter)
+}
+
+func splitJSONFrontMatter(data string) (FrontMatterFormat, string, string, error) {
+	decoder := json.NewDecoder(strings.NewReader(data))
+	var object map[string]any
+	if err := decoder.Decode(&object); err != nil {
+		return FrontMatterNone, "", "", fmt.Errorf("could not parse json frontmatter: %w", err)
+	}
+	end := int(decoder.InputOffset())
+	body := data[end:]
+	// the rest of the closing line belongs to the front matter
+	if i := strings.IndexByte(body, '\n'); i >= 0 && strings.TrimSpace(body[:i]) == "" {
+		body = body[i+1:]
+	}
+	return FrontMatterJSON, data[:end], body, nil
+}
+
+// ParseFrontMatter parses raw front matter of the given format into a map.
+func ParseFrontMatter(format FrontMatterFormat, raw string) (map[string]any, error) {
+	frontMatterMap := map[string]any{}
+	switch format {
+	case FrontMatterYAML:
+		if err := yaml.Unmarshal([]byte(raw), &frontMatterMap); err != nil {
+			return nil, fmt.Errorf("could not parse frontmatter: %w", err)
+		}
+	case FrontMatterTOML:
+		if err := toml.Unmarshal([]byte(raw), &frontMatterMap); err != nil {
+			return nil, fmt.Errorf("could not parse toml frontmatter: %w", err)
+		}
+		// local dates and times have no YAML representation, pass them on as strings
+		for key, value := range frontMatterMap {
+			switch v := value.(type) {
+			case toml.LocalDateTime:
+				frontMatterMap[key] = v.String()
+			case toml.LocalDate:
+				frontMatterMap[key] = v.String()
+			}
+		}
+	case FrontMatterJSON:
+		if err := json.Unmarshal([]byte(raw), &frontMatterMap); err != nil {
+			return nil, fmt.Errorf("could not parse json frontmatter: %w", err)
+		}
+	}
+	return frontMatterMap, nil
+}
+
+// UpdateFrontMatterFile sets keys in the front matter of a Markdown file in place,
+// keeping its YAML, TOML or JSON format. Existing keys keep their position and
+// comments, new keys are appended in key order, and a YAML front matter block is
+// created if the file has none. The body is not touched, and a BOM and CRLF line
+// endings are kept.
+func UpdateFrontMatterFile(filepath string, values map[string]any) error {
+	data, err := os.ReadFile(filepath)
+	if err != nil {
+		return fmt.Errorf("could not read file: %w", err)
+	}
+
+	format, raw, body, err := SplitFrontMatter(string(data))
+	if err != nil {
+		return err
+	}
+	// the front matter is edited with LF line endings and written with the ones of the file
+	newline := "\n"
+	if i := strings.IndexByte(string(data), '\n'); i > 0 && data[i-1] == '\r' {
+		newline = "\r\n"
+	}
+	raw = strings.ReplaceAll(raw, "\r\n", "\n")
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	switch format {
+	case FrontMatterTOML:
+		updated, err := updateTOMLFrontMatter(raw, keys, values)
+		if err != nil {
+			return err
+		}
+		buf.WriteString("+++\n" + updated + "+++\n")
+	case FrontMatterJSON:
+		updated, err := updateJSONFrontMatter(raw, keys, values)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(updated + "\n")
+	default:
+		updated, err := updateYAMLFrontMatter(raw, keys, values)
+		if err != nil {
+			return err
+		}
+		buf.WriteString("---\n" + updated + "---\n")
+		if format == FrontMatterNone {
+			buf.WriteString("\n")
+		}
+	}
+	header := strings.ReplaceAll(buf.String(), "\n", newline)
+	if strings.HasPrefix(string(data), bom) {
+		header = bom + header
+	}
+
+	info, err := os.Stat(filepath)
+	if err != nil {
+		return fmt.Errorf("could not stat file: %w", err)
+	}
+	if err := os.WriteFile(filepath, []byte(header+body), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("could not write file: %w", err)
+	}
+	return nil
+}
+
+// updateMappingNode sets keys in a YAML mapping node, keeping the comments and
+// quoting style of the values it replaces.
+func updateMappingNode(mapping *yamlv3.Node, keys []string, values map[string]any) error {
+	for _, key := range keys {
+		value := &yamlv3.Node{}
+		if err := value.Encode(values[key]); err != nil {
+			return fmt.Errorf("could not encode %s: %w", key, err)
+		}
+
+		found := false
+		for i := 0; i+1 < len(mapping.Content); i += 2 {
+			if mapping.Content[i].Value == key {
+				old := mapping.Content[i+1]
+				value.HeadComment, value.LineComment, value.FootComment = old.HeadComment, old.LineComment, old.FootComment
+				if old.Kind == yamlv3.ScalarNode && value.Kind == yamlv3.ScalarNode && value.Tag == "!!str" {
+					value.Style = old.Style
+				}
+				mapping.Content[i+1] = value
+				found = true
+				break
+			}
+		}
+		if !found {
+			mapping.Content = append(mapping.Content, &yamlv3.Node{Kind: yamlv3.ScalarNode, Value: key}, value)
+		}
+	}
+	return nil
+}
+
+func parseMappingNode(data string) (*yamlv3.Node, error) {
+	doc := &yamlv3.Node{}
+	if err := yamlv3.Unmarshal([]byte(data), doc); err != nil {
+		return nil, fmt.Errorf("could not parse frontmatter: %w", err)
+	}
+	if doc.Kind == 0 {
+		doc = &yamlv3.Node{Kind: yamlv3.DocumentNode, Content: []*yamlv3.Node{{Kind: yamlv3.MappingNode}}}
+	}
+	if doc.Content[0].Kind != yamlv3.MappingNode {
+		return nil, fmt.Errorf("frontmatter is not a mapping")
+	}
+	return doc, nil
+}
+
+func updateYAMLFrontMatter(data string, keys []string, values map[string]any) (string, error) {
+	doc, err := parseMappingNode(data)
+	if err != nil {
+		return "", err
+	}
+	if err := updateMappingNode(doc.Content[0], keys, values); err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	encoder := yamlv3.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return "", fmt.Errorf("could not marshal frontmatter: %w", err)
+	}
+	encoder.Close()
+	return buf.String(), nil
+}
+
+// updateJSONFrontMatter edits the object through the YAML node API, which keeps
+// the key order, since JSON is a subset of YAML.
+func updateJSONFrontMatter(data string, keys []string, values map[string]any) (string, error) {
+	doc, err := parseMappingNode(data)
+	if err != nil {
+		return "", err
+	}
+	if err := updateMappingNode(doc.Content[0], keys, values); err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := writeJSONNode(&buf, doc.Content[0], ""); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func writeJSONNode(buf *bytes.Buffer, node *yamlv3.Node, indent string) error {
+	switch node.Kind {
+	case yamlv3.MappingNode, yamlv3.SequenceNode:
+		open, close, step := "{", "}", 2
+		if node.Kind == yamlv3.SequenceNode {
+			open, close, step = "[", "]", 1
+		}
+		if len(node.Content) == 0 {
+			buf.WriteString(open + close)
+			return nil
+		}
+		if node.Kind == yamlv3.SequenceNode && isScalarSequence(node) {
+			buf.WriteString(open)
+			for i, item := range node.Content {
+				if i > 0 {
+					buf.WriteString(", ")
+				}
+				writeJSONNode(buf, item, indent)
+			}
+			buf.WriteString(close)
+			return nil
+		}
+		buf.WriteString(open + "\n")
+		for i := 0; i < len(node.Content); i += step {
+			buf.WriteString(indent + "  ")
+			if step == 2 {
+				buf.WriteString(jsonString(node.Content[i].Value) + ": ")
+			}
+			if err := writeJSONNode(buf, node.Content[i+step-1], indent+"  "); err != nil {
+				return err
+			}
+			if i+step < len(node.Content) {
+				buf.WriteString(",")
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString(indent + close)
+	case yamlv3.ScalarNode:
+		switch node.Tag {
+		case "!!int", "!!float", "!!bool", "!!null":
+			buf.WriteString(node.Value)
+		default:
+			buf.WriteString(jsonString(node.Value))
+		}
+	case yamlv3.AliasNode:
+		return writeJSONNode(buf, node.Alias, indent)
+	default:
+		return fmt.Errorf("unsupported json value")
+	}
+	return nil
+}
+
+func isScalarSequence(node *yamlv3.Node) bool {
+	for _, item := range node.Content {
+		if item.Kind != yamlv3.ScalarNode {
+			return false
+		}
+	}
+	return true
+}
+
+// updateTOMLFrontMatter rewrites the top-level `key = value` lines in place, so
+// comments and tables are kept, and appends new keys before the first table.
+func updateTOMLFrontMatter(data string, keys []string, values map[string]any) (string, error) {
+	lines := strings.SplitAfter(data, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	firstTable := len(lines)
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "[") {
+			firstTable = i
+			break
+		}
+	}
+
+	for _, key := range keys {
+		line, err := encodeTOMLLine(key, values[key])
+		if err != nil {
+			return "", err
+		}
+
+		found := false
+		for i := 0; i < firstTable; i++ {
+			name, _, ok := strings.Cut(lines[i], "=")
+			if ok && strings.Trim(strings.TrimSpace(name), `"'`) == key {
+				lines[i] = line
+				found = true
+				break
+			}
+		}
+		if !found {
+			// keep the blank lines separating the keys from the first table
+			at := firstTable
+			for at > 0 && strings.TrimSpace(lines[at-1]) == "" {
+				at--
+			}
+			lines = append(lines[:at], append([]string{line}, lines[at:]...)...)
+			firstTable++
+		}
+	}
+
+	result := strings.Join(lines, "")
+	if result != "" && !strings.HasSuffix(result, "\n") {
+		result += "\n"
+	}
+	return result, nil
+}
+
+func encodeTOMLLine(key string, value any) (string, error) {
+	// go-toml writes literal 'strings', most files use "basic strings"
+	if str, ok := value.(string); ok {
+		return key + " = " + jsonString(str) + "\n", nil
+	}
+	encoded, err := toml.Marshal(map[string]any{key: value})
+	if err != nil {
+		return "", fmt.Errorf("could not encode %s: %w", key, err)
+	}
+	return string(encoded), nil
+}
+
+// jsonString quotes s as a JSON string, which is also a valid TOML basic string.
+func jsonString(s string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
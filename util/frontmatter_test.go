@@ -0,0 +1,171 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSplitFrontMatter(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		format  FrontMatterFormat
+		raw     string
+		body    string
+		wantErr bool
+	}{
+		{
+			name: "none",
+			data: "# Title\n",
+			body: "# Title\n",
+		},
+		{
+			name:   "yaml",
+			data:   "---\ntitle: Hello\n---\nbody\n",
+			format: FrontMatterYAML,
+			raw:    "title: Hello\n",
+			body:   "body\n",
+		},
+		{
+			name:   "yaml with crlf",
+			data:   "---\r\ntitle: Hello\r\n---\r\nbody\r\n",
+			format: FrontMatterYAML,
+			raw:    "title: Hello\r\n",
+			body:   "body\r\n",
+		},
+		{
+			name:   "yaml with bom",
+			data:   "\uFEFF---\ntitle: Hello\n---\nbody\n",
+			format: FrontMatterYAML,
+			raw:    "title: Hello\n",
+			body:   "body\n",
+		},
+		{
+			name: "unclosed yaml is a thematic break",
+			data: "---\ntext\n",
+			body: "---\ntext\n",
+		},
+		{
+			name: "thematic break in body",
+			data: "text\n\n---\n\nmore\n",
+			body: "text\n\n---\n\nmore\n",
+		},
+		{
+			name:   "toml",
+			data:   "+++\ntitle = \"Hello\"\n+++\nbody\n",
+			format: FrontMatterTOML,
+			raw:    "title = \"Hello\"\n",
+			body:   "body\n",
+		},
+		{
+			name:    "unclosed toml",
+			data:    "+++\ntitle = \"Hello\"\n",
+			wantErr: true,
+		},
+		{
+			name:   "json",
+			data:   "{\n  \"title\": \"Hello\"\n}\nbody\n",
+			format: FrontMatterJSON,
+			raw:    "{\n  \"title\": \"Hello\"\n}",
+			body:   "body\n",
+		},
+		{
+			name:   "single line json",
+			data:   "{\"title\": \"Hello\"}\n\nbody\n",
+			format: FrontMatterJSON,
+			raw:    "{\"title\": \"Hello\"}",
+			body:   "\nbody\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, raw, body, err := SplitFrontMatter(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SplitFrontMatter(%q) error = %v, wantErr %v", tt.data, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if format != tt.format || raw != tt.raw || body != tt.body {
+				t.Errorf("SplitFrontMatter(%q) = %q, %q, %q, want %q, %q, %q", tt.data, format, raw, body, tt.format, tt.raw, tt.body)
+			}
+		})
+	}
+}
+
+func TestUpdateFrontMatterFile(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		values map[string]any
+		want   string
+	}{
+		{
+			name:   "yaml",
+			data:   "---\ntitle: Hello # greeting\nslug: old\n---\nbody\n",
+			values: map[string]any{"slug": "new", "id": 1},
+			want:   "---\ntitle: Hello # greeting\nslug: new\nid: 1\n---\nbody\n",
+		},
+		{
+			name:   "no front matter",
+			data:   "body\n",
+			values: map[string]any{"slug": "new"},
+			want:   "---\nslug: new\n---\n\nbody\n",
+		},
+		{
+			name:   "toml",
+			data:   "+++\n# comment\ntitle = \"Hello\"\n\n[extra]\nslug = \"kept\"\n+++\nbody\n",
+			values: map[string]any{"title": "Hi", "slug": "new"},
+			want:   "+++\n# comment\ntitle = \"Hi\"\nslug = \"new\"\n\n[extra]\nslug = \"kept\"\n+++\nbody\n",
+		},
+		{
+			name:   "json",
+			data:   "{\n  \"title\": \"Hello\",\n  \"tags\": [\"a\", \"b\"]\n}\nbody\n",
+			values: map[string]any{"id": 1},
+			want:   "{\n  \"title\": \"Hello\",\n  \"tags\": [\"a\", \"b\"],\n  \"id\": 1\n}\nbody\n",
+		},
+		{
+			name:   "bom",
+			data:   "\uFEFF---\ntitle: Hello\n---\nbody\n",
+			values: map[string]any{"slug": "new"},
+			want:   "\uFEFF---\ntitle: Hello\nslug: new\n---\nbody\n",
+		},
+		{
+			name:   "crlf yaml",
+			data:   "---\r\ntitle: Hello\r\n---\r\nbody\r\n",
+			values: map[string]any{"slug": "new"},
+			want:   "---\r\ntitle: Hello\r\nslug: new\r\n---\r\nbody\r\n",
+		},
+		{
+			name:   "crlf toml",
+			data:   "+++\r\ntitle = \"Hello\"\r\n+++\r\nbody\r\n",
+			values: map[string]any{"slug": "new"},
+			want:   "+++\r\ntitle = \"Hello\"\r\nslug = \"new\"\r\n+++\r\nbody\r\n",
+		},
+		{
+			name:   "crlf without front matter",
+			data:   "\uFEFFbody\r\n",
+			values: map[string]any{"slug": "new"},
+			want:   "\uFEFF---\r\nslug: new\r\n---\r\n\r\nbody\r\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "post.md")
+			if err := os.WriteFile(file, []byte(tt.data), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := UpdateFrontMatterFile(file, tt.values); err != nil {
+				t.Fatalf("UpdateFrontMatterFile() error = %v", err)
+			}
+			got, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("UpdateFrontMatterFile() wrote %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+)
+
+// HTMLToMarkdown converts an HTML fragment, such as the content of a feed entry, to Markdown.
+func HTMLToMarkdown(html string) (string, error) {
+	converter := md.NewConverter("", true, &md.Options{
+		HeadingStyle:     "atx",
+		CodeBlockStyle:   "fenced",
+		BulletListMarker: "-",
+	})
+	markdown, err := converter.ConvertString(html)
+	if err != nil {
+		return "", fmt.Errorf("could not convert html to markdown: %w", err)
+	}
+	return strings.TrimSpace(markdown) + "\n", nil
+}
@@ -0,0 +1,31 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	markdownImageRe = regexp.MustCompile(`!\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+	htmlImageRe     = regexp.MustCompile(`(?i)<img[^>]+src=["']([^"']+)["']`)
+)
+
+// ExtractImageURLs returns the remote (http/https) image URLs referenced by Markdown
+// image syntax or <img> tags in content, without duplicates.
+func ExtractImageURLs(content string) []string {
+	seen := map[string]bool{}
+	urls := []string{}
+	for _, re := range []*regexp.Regexp{markdownImageRe, htmlImageRe} {
+		for _, match := range re.FindAllStringSubmatch(content, -1) {
+			url := match[1]
+			if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+				continue
+			}
+			if !seen[url] {
+				seen[url] = true
+				urls = append(urls, url)
+			}
+		}
+	}
+	return urls
+}
@@ -0,0 +1,192 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// includeRe matches {{< include "path" lines="10-40" >}}, also with {{% %}}.
+	includeRe    = regexp.MustCompile(`\{\{[<%]\s*include\s+((?:[^>%]|>[^}]|%[^}])*?)\s*[>%]\}\}`)
+	includeArgRe = regexp.MustCompile(`(?:(\w+)=)?"([^"]*)"`)
+
+	// region markers on lines of their own, in any comment syntax: `// region name`
+	// and `// endregion` as in VS Code, or `// tag::name[]` and `// end::name[]` as in Asciidoctor.
+	regionStartRe = regexp.MustCompile(`^\s*(?:(?://|#|--|;|/\*|<!--|\(\*)\s*)?(?:#?region\s+([\w.-]+)|tag::([\w.-]+)\[\])`)
+	regionEndRe   = regexp.MustCompile(`^\s*(?:(?://|#|--|;|/\*|<!--|\(\*)\s*)?(?:#?endregion\b\s*([\w.-]*)|end::([\w.-]+)\[\])`)
+
+	languages = map[string]string{
+		".go": "go", ".py": "python", ".js": "javascript", ".mjs": "javascript", ".jsx": "jsx",
+		".ts": "typescript", ".tsx": "tsx", ".rs": "rust", ".rb": "ruby", ".java": "java",
+		".kt": "kotlin", ".swift": "swift", ".c": "c", ".h": "c", ".cc": "cpp", ".cpp": "cpp",
+		".hpp": "cpp", ".cs": "csharp", ".php": "php", ".sh": "bash", ".bash": "bash",
+		".zsh": "zsh", ".ps1": "powershell", ".sql": "sql", ".html": "html", ".css": "css",
+		".scss": "scss", ".json": "json", ".yaml": "yaml", ".yml": "yaml", ".toml": "toml",
+		".xml": "xml", ".md": "markdown", ".lua": "lua", ".dart": "dart", ".ex": "elixir",
+		".exs": "elixir", ".erl": "erlang", ".hs": "haskell", ".scala": "scala", ".vue": "vue",
+		".proto": "protobuf", ".tf": "hcl", ".diff": "diff", ".ini": "ini",
+	}
+	languageFiles = map[string]string{
+		"dockerfile": "dockerfile", "makefile": "makefile", "go.mod": "go", "cmakelists.txt": "cmake",
+	}
+)
+
+// Language guesses the language of a code file for the info string of a fenced code block.
+func Language(file string) string {
+	base := strings.ToLower(filepath.Base(file))
+	if lang, ok := languageFiles[base]; ok {
+		return lang
+	}
+	return languages[strings.ToLower(filepath.Ext(base))]
+}
+
+// ExpandIncludes replaces include directives in body with fenced code blocks holding
+// the included file, or part of it. Paths are relative to dir. The directive has the
+// arguments lines="10-40" (ranges separated by commas), region="name" and lang="go".
+func ExpandIncludes(body, dir string) (string, error) {
+	var firstErr error
+	body = ReplaceOutsideCode(body, includeRe, func(match []string) string {
+		code, err := include(match[1], dir)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("could not expand %s: %w", match[0], err)
+			}
+			return match[0]
+		}
+		return code
+	})
+	return body, firstErr
+}
+
+func include(args, dir string) (string, error) {
+	path := ""
+	attrs := map[string]string{}
+	for _, arg := range includeArgRe.FindAllStringSubmatch(args, -1) {
+		switch arg[1] {
+		case "", "file", "path":
+			path = arg[2]
+		default:
+			attrs[arg[1]] = arg[2]
+		}
+	}
+	if path == "" {
+		return "", fmt.Errorf("no file given")
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(strings.TrimRight(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n"), "\n")
+
+	if region := attrs["region"]; region != "" {
+		if lines, err = selectRegion(lines, region); err != nil {
+			return "", err
+		}
+	}
+	if spec := attrs["lines"]; spec != "" {
+		if lines, err = selectLines(lines, spec); err != nil {
+			return "", err
+		}
+	}
+	lines = dedent(lines)
+
+	lang := attrs["lang"]
+	if lang == "" {
+		lang = Language(path)
+	}
+	code := strings.Join(lines, "\n")
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return fence + lang + "\n" + code + "\n" + fence, nil
+}
+
+// selectRegion returns the lines between the markers of a region, without the
+// marker lines of any region.
+func selectRegion(lines []string, region string) ([]string, error) {
+	selected := []string{}
+	inside, found := false, false
+	for _, line := range lines {
+		if m := regionStartRe.FindStringSubmatch(line); m != nil {
+			if m[1] == region || m[2] == region {
+				inside, found = true, true
+			}
+			continue
+		}
+		if m := regionEndRe.FindStringSubmatch(line); m != nil {
+			name := m[1] + m[2]
+			if inside && (name == "" || name == region) {
+				inside = false
+			}
+			continue
+		}
+		if inside {
+			selected = append(selected, line)
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("region %s not found", region)
+	}
+	return selected, nil
+}
+
+// selectLines returns the lines of ranges such as "10-40", "10-", "-5" or "3", 1-based
+// and separated by commas.
+func selectLines(lines []string, spec string) ([]string, error) {
+	selected := []string{}
+	for _, part := range strings.Split(spec, ",") {
+		from, to, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		start, end := 1, len(lines)
+		var err error
+		if from != "" {
+			if start, err = strconv.Atoi(from); err != nil {
+				return nil, fmt.Errorf("invalid lines %q", spec)
+			}
+		}
+		if !isRange {
+			end = start
+		} else if to != "" {
+			if end, err = strconv.Atoi(to); err != nil {
+				return nil, fmt.Errorf("invalid lines %q", spec)
+			}
+		}
+		if start < 1 || end > len(lines) || start > end {
+			return nil, fmt.Errorf("lines %q out of range, the file has %d lines", part, len(lines))
+		}
+		selected = append(selected, lines[start-1:end]...)
+	}
+	return selected, nil
+}
+
+// dedent removes the indentation all non-blank lines have in common.
+func dedent(lines []string) []string {
+	prefix := ""
+	first := true
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if first {
+			prefix, first = indent, false
+			continue
+		}
+		for !strings.HasPrefix(indent, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		out[i] = strings.TrimPrefix(line, prefix)
+	}
+	return out
+}
@@ -0,0 +1,97 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExpandIncludes(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.go":  "package main\n\n// region setup\nfunc setup() {\n\tinit()\n}\n// endregion\n\nfunc main() {\n\tsetup()\n}\n",
+		"tags.py":  "import os\n\nclass A:\n    # tag::method[]\n    def run(self):\n        pass\n    # end::method[]\n",
+		"crlf.sh":  "echo one\r\necho two\r\n",
+		"fence.md": "```go\ncode\n```\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "whole file",
+			body: `{{< include "crlf.sh" >}}`,
+			want: "```bash\necho one\necho two\n```",
+		},
+		{
+			name: "lines",
+			body: `{{< include "main.go" lines="1,9-11" >}}`,
+			want: "```go\npackage main\nfunc main() {\n\tsetup()\n}\n```",
+		},
+		{
+			name: "region",
+			body: `{{% include "main.go" region="setup" %}}`,
+			want: "```go\nfunc setup() {\n\tinit()\n}\n```",
+		},
+		{
+			name: "asciidoctor tag is dedented",
+			body: `{{< include "tags.py" region="method" lang="py" >}}`,
+			want: "```py\ndef run(self):\n    pass\n```",
+		},
+		{
+			name: "longer fence",
+			body: `{{< include "fence.md" >}}`,
+			want: "````markdown\n```go\ncode\n```\n````",
+		},
+		{
+			name: "directive in code is kept",
+			body: "```\n{{< include \"main.go\" >}}\n```",
+			want: "```\n{{< include \"main.go\" >}}\n```",
+		},
+		{
+			name:    "missing file",
+			body:    `{{< include "missing.go" >}}`,
+			want:    `{{< include "missing.go" >}}`,
+			wantErr: true,
+		},
+		{
+			name:    "missing region",
+			body:    `{{< include "main.go" region="teardown" >}}`,
+			want:    `{{< include "main.go" region="teardown" >}}`,
+			wantErr: true,
+		},
+		{
+			name:    "lines out of range",
+			body:    `{{< include "crlf.sh" lines="2-5" >}}`,
+			want:    `{{< include "crlf.sh" lines="2-5" >}}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandIncludes(tt.body, dir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExpandIncludes(%q) error = %v, wantErr %v", tt.body, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ExpandIncludes(%q) = %q, want %q", tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	for file, want := range map[string]string{"main.go": "go", "Dockerfile": "dockerfile", "app.TSX": "tsx", "notes.txt": ""} {
+		if got := Language(file); got != want {
+			t.Errorf("Language(%q) = %q, want %q", file, got, want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package util
+
+import (
+	"bytes"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// Link is a link or image in the body of a document.
+type Link struct {
+	Destination string
+	Text        string
+	Image       bool
+	// Offset is the position of the destination in Body, see Document.Position.
+	Offset int
+}
+
+// IsRelative reports whether the link points to a local file, not to a URL,
+// an absolute path or an anchor in the same document.
+func (l Link) IsRelative() bool {
+	dest := l.Destination
+	if dest == "" || strings.HasPrefix(dest, "#") || strings.HasPrefix(dest, "/") || strings.HasPrefix(dest, "//") {
+		return false
+	}
+	u, err := url.Parse(dest)
+	return err == nil && u.Scheme == ""
+}
+
+// Path returns the unescaped path of a relative link without its query and fragment.
+func (l Link) Path() string {
+	path := l.Destination
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	if unescaped, err := url.PathUnescape(path); err == nil {
+		path = unescaped
+	}
+	return path
+}
+
+// Links returns the links and images of the body in document order.
+func (d *Document) Links() []Link {
+	links := []Link{}
+	ast.Walk(d.AST(), func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.Link:
+			links = append(links, d.newLink(node, string(node.Destination), false))
+		case *ast.Image:
+			links = append(links, d.newLink(node, string(node.Destination), true))
+		case *ast.AutoLink:
+			if node.AutoLinkType == ast.AutoLinkURL {
+				links = append(links, d.newLink(node, string(node.URL(d.Body)), false))
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return links
+}
+
+func (d *Document) newLink(n ast.Node, dest string, image bool) Link {
+	link := Link{Destination: dest, Image: image, Offset: d.inlineOffset(n)}
+	if text := nodeText(n, d.Body); text != "" {
+		link.Text = text
+	}
+	if dest == "" {
+		return link
+	}
+	// skip the link text, which may contain the destination too
+	rest := d.Body[link.Offset:]
+	if i := bytes.Index(rest, []byte("]("+dest)); i >= 0 {
+		link.Offset += i + 2
+	} else if i := bytes.Index(rest, []byte("](<"+dest)); i >= 0 {
+		link.Offset += i + 3
+	} else if i := bytes.Index(rest, []byte(dest)); i >= 0 {
+		link.Offset += i
+	}
+	return link
+}
+
+// RewriteLinks replaces the destinations of the links and images of a Markdown body
+// with the ones returned by fn. Links are kept if fn returns false, or if their
+// destination is not written literally in the body, e.g. because it is escaped.
+func RewriteLinks(body string, fn func(Link) (string, bool)) string {
+	doc := &Document{Body: []byte(body)}
+	links := doc.Links()
+	// replace from the end, so the offsets of earlier links stay valid. Links sharing
+	// a reference definition have the same offset, it is only replaced once.
+	sort.SliceStable(links, func(i, j int) bool { return links[i].Offset > links[j].Offset })
+	for i, link := range links {
+		end := link.Offset + len(link.Destination)
+		if link.Destination == "" || end > len(body) || body[link.Offset:end] != link.Destination {
+			continue
+		}
+		if i > 0 && links[i-1].Offset == link.Offset {
+			continue
+		}
+		if dest, ok := fn(link); ok {
+			body = body[:link.Offset] + dest + body[end:]
+		}
+	}
+	return body
+}
+
+// inlineOffset approximates the position of an inline node, which goldmark does not
+// record, by its first text segment or else the start of its block.
+func (d *Document) inlineOffset(n ast.Node) int {
+	for c := n.FirstChild(); c != nil; c = c.FirstChild() {
+		if text, ok := c.(*ast.Text); ok {
+			return text.Segment.Start
+		}
+	}
+	for p := n.Parent(); p != nil; p = p.Parent() {
+		if p.Type() == ast.TypeBlock && p.Lines().Len() > 0 {
+			return p.Lines().At(0).Start
+		}
+	}
+	return 0
+}
+
+// nodeText concatenates the text segments below n.
+func nodeText(n ast.Node, source []byte) string {
+	var buf bytes.Buffer
+	ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if text, ok := c.(*ast.Text); ok && entering {
+			buf.Write(text.Segment.Value(source))
+		}
+		return ast.WalkContinue, nil
+	})
+	return buf.String()
+}
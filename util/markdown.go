@@ -1,53 +1,128 @@
 package util
 
 import (
-	"bufio"
+	"bytes"
 	"fmt"
 	"os"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/quail-ink/quail-cli/core"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/text"
+	yaml "gopkg.in/yaml.v2"
 )
 
-func ParseMarkdownWithFrontMatter(filepath string, frontMatterMapping map[string]string) (*core.QuailPostFrontMatter, string, error) {
-	file, err := os.Open(filepath)
+// Document is a Markdown file split into its front matter and body.
+type Document struct {
+	Format         FrontMatterFormat
+	RawFrontMatter string
+	FrontMatter    map[string]any
+	// Body is the Markdown after the front matter, byte for byte as in the file.
+	Body []byte
+	// BodyLine is the 1-based line of the file on which Body starts.
+	BodyLine int
+
+	ast ast.Node
+}
+
+var markdownParser = goldmark.New(goldmark.WithExtensions(extension.GFM)).Parser()
+
+// ReadDocument reads and parses a Markdown file.
+func ReadDocument(filepath string) (*Document, error) {
+	data, err := os.ReadFile(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("could not open file: %w", err)
+	}
+	return ParseDocument(data)
+}
+
+// ParseDocument splits data into front matter and body and parses the front matter.
+func ParseDocument(data []byte) (*Document, error) {
+	format, raw, body, err := SplitFrontMatter(string(data))
 	if err != nil {
-		return nil, "", fmt.Errorf("could not open file: %w", err)
+		return nil, err
 	}
-	defer file.Close()
 
+	frontMatterMap, err := ParseFrontMatter(format, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	header := strings.TrimPrefix(string(data), bom)
+	header = header[:len(header)-len(body)]
+
+	return &Document{
+		Format:         format,
+		RawFrontMatter: raw,
+		FrontMatter:    frontMatterMap,
+		Body:           []byte(body),
+		BodyLine:       strings.Count(header, "\n") + 1,
+	}, nil
+}
+
+// AST parses the body as CommonMark with GitHub extensions. The positions of
+// the nodes are offsets into Body. The tree is parsed once and then cached.
+func (d *Document) AST() ast.Node {
+	if d.ast == nil {
+		d.ast = markdownParser.Parse(text.NewReader(d.Body))
+	}
+	return d.ast
+}
+
+// Position converts an offset into Body to a 1-based line and column of the file.
+func (d *Document) Position(offset int) (line, col int) {
+	if offset > len(d.Body) {
+		offset = len(d.Body)
+	}
+	before := d.Body[:offset]
+	line = d.BodyLine + bytes.Count(before, []byte("\n"))
+	col = utf8.RuneCount(before[bytes.LastIndexByte(before, '\n')+1:]) + 1
+	return line, col
+}
+
+// QuailFrontMatter converts the front matter to the fields Quail knows, renaming
+// and transforming keys with frontMatterMapping. Datetimes without an offset are in loc.
+func (d *Document) QuailFrontMatter(frontMatterMapping core.FrontMatterMapping, loc *time.Location) (*core.QuailPostFrontMatter, error) {
 	frontMatter := &core.QuailPostFrontMatter{}
-	var content strings.Builder
-	var isFrontMatter bool
-	var frontMatterLines []string
-
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		if strings.TrimSpace(line) == "---" {
-			if isFrontMatter {
-				if err := frontMatter.LoadFromYAML(strings.Join(frontMatterLines, "\n"), frontMatterMapping); err != nil {
-					return nil, "", fmt.Errorf("could not parse frontmatter: %w", err)
-				}
-
-				isFrontMatter = false
-			} else {
-				isFrontMatter = true
-			}
-			continue
-		}
+	if err := frontMatter.LoadFromMap(d.FrontMatter, frontMatterMapping, loc); err != nil {
+		return nil, fmt.Errorf("could not parse frontmatter: %w", err)
+	}
+	return frontMatter, nil
+}
 
-		if isFrontMatter {
-			frontMatterLines = append(frontMatterLines, line)
-		} else {
-			content.WriteString(line + "\n")
+// RenderMarkdownWithFrontMatter is the reverse of ParseDocument: it writes the front
+// matter as a YAML block, using the mapped key names, followed by the content.
+func RenderMarkdownWithFrontMatter(frontMatter *core.QuailPostFrontMatter, content string, frontMatterMapping core.FrontMatterMapping) (string, error) {
+	fields := yaml.MapSlice{
+		{Key: "title", Value: frontMatter.Title},
+		{Key: "slug", Value: frontMatter.Slug},
+		{Key: "datetime", Value: ""},
+		{Key: "summary", Value: frontMatter.Summary},
+		{Key: "tags", Value: frontMatter.Tags},
+		{Key: "cover_image_url", Value: frontMatter.CoverImageUrl},
+		{Key: "theme", Value: frontMatter.Theme},
+	}
+	if frontMatter.Datetime != nil {
+		fields[2].Value = frontMatter.Datetime.Format(time.RFC3339)
+	}
+
+	items := yaml.MapSlice{}
+	for _, field := range fields {
+		if field.Value == "" {
+			continue
 		}
+		field.Key = frontMatterMapping.Name(field.Key.(string))
+		items = append(items, field)
 	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, "", fmt.Errorf("error reading file: %w", err)
+	data, err := yaml.Marshal(items)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal frontmatter: %w", err)
 	}
 
-	return frontMatter, content.String(), nil
+	return "---\n" + string(data) + "---\n\n" + content, nil
 }
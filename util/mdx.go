@@ -0,0 +1,94 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// jsxTagRe matches the tags of components, which start with an uppercase letter
+	// unlike HTML tags: <Name attr="value">, </Name> and <Name />.
+	jsxTagRe     = regexp.MustCompile(`<(/?)([A-Z][\w.]*)((?:\s+[\w:-]+(?:\s*=\s*(?:"[^"]*"|'[^']*'|\{[^}]*\}))?)*)\s*(/?)>`)
+	jsxAttrRe    = regexp.MustCompile(`([\w:-]+)(?:\s*=\s*(?:"([^"]*)"|'([^']*)'|\{([^}]*)\}))?`)
+	jsxCommentRe = regexp.MustCompile(`\{/\*[\s\S]*?\*/\}`)
+	// mdxModuleRe matches import and single line export statements of MDX files.
+	mdxModuleRe = regexp.MustCompile(`(?m)^(?:import\s.+|export\s+(?:const|let|var|function|default)\s.+)\n?`)
+)
+
+// ConvertMDX replaces common MDX components with Markdown Quail can show, like
+// ConvertShortcodes. Callout components such as <Callout type="warning"> or <Note>
+// become blockquotes. Unknown components are removed, keeping their children, and
+// reported to warn. Tags without a closing tag are not components and are kept.
+// If modules is set, import and export statements are removed too.
+func ConvertMDX(body string, modules bool, warn func(string)) string {
+	body = ReplaceOutsideCode(body, jsxCommentRe, func([]string) string { return "" })
+	if modules {
+		body = ReplaceOutsideCode(body, mdxModuleRe, func([]string) string { return "" })
+	}
+	return convertElements(body, findComponents, func(e element, inner string, paired bool) string {
+		switch strings.ToLower(e.name) {
+		case "youtube", "youtubeembed":
+			id := e.arg("id", -1)
+			if id == "" {
+				id = e.arg("videoId", -1)
+			}
+			return "https://www.youtube.com/watch?v=" + id
+		case "vimeo":
+			return "https://vimeo.com/" + e.arg("id", -1)
+		case "tweet":
+			return "https://x.com/i/status/" + e.arg("id", -1)
+		case "gist":
+			return "https://gist.github.com/" + e.arg("id", -1)
+		case "image", "img", "figure":
+			return formatFigure(e.arg("src", -1), e.arg("alt", -1), e.arg("title", -1), e.arg("caption", -1), e.arg("href", -1))
+		case "callout", "admonition", "aside":
+			kind := e.arg("type", -1)
+			if kind == "" {
+				kind = "note"
+			}
+			return formatCallout(kind, e.arg("title", -1), inner)
+		case "note", "tip", "info", "warning", "caution", "danger", "important":
+			return formatCallout(e.name, e.arg("title", -1), inner)
+		case "details":
+			return formatDetails(e.arg("summary", -1), inner)
+		}
+		if !paired && !e.selfClosing {
+			// not a component, e.g. the type parameter in List<String>
+			return e.raw
+		}
+		warn(fmt.Sprintf("unknown component <%s> removed", e.name))
+		return strings.Trim(inner, "\n")
+	})
+}
+
+func findComponents(body string) []element {
+	ranges := codeRanges(body)
+	elements := []element{}
+	for _, loc := range jsxTagRe.FindAllStringSubmatchIndex(body, -1) {
+		if inRanges(ranges, loc[0]) {
+			continue
+		}
+		e := element{
+			start:       loc[0],
+			end:         loc[1],
+			raw:         body[loc[0]:loc[1]],
+			name:        body[loc[4]:loc[5]],
+			args:        map[string]string{},
+			closing:     loc[3] > loc[2],
+			selfClosing: loc[9] > loc[8],
+		}
+		for _, attr := range jsxAttrRe.FindAllStringSubmatch(body[loc[6]:loc[7]], -1) {
+			value := attr[2] + attr[3]
+			if expr := strings.TrimSpace(attr[4]); expr != "" {
+				// {"value"}, {`value`} or an expression such as {42}
+				value = strings.Trim(expr, "\"'`")
+			} else if attr[0] == attr[1] {
+				value = "true"
+			}
+			e.args[attr[1]] = value
+		}
+		elements = append(elements, e)
+	}
+	return elements
+}
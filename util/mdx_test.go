@@ -0,0 +1,102 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConvertMDX(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		modules  bool
+		want     string
+		warnings int
+	}{
+		{
+			name: "youtube",
+			body: `<YouTube id="dQw4w9WgXcQ" />`,
+			want: "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+		},
+		{
+			name: "expression attribute",
+			body: `<YouTubeEmbed videoId={"dQw4w9WgXcQ"} />`,
+			want: "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+		},
+		{
+			name: "image",
+			body: `<Image src="/a.png" alt='A' title="T" caption="C" />`,
+			want: "![A](/a.png \"T\")\n\n*C*",
+		},
+		{
+			name: "callout",
+			body: "<Callout type=\"warning\" title=\"Careful\">\nDo **not** do this.\n</Callout>",
+			want: "> [!warning] Careful\n> Do **not** do this.",
+		},
+		{
+			name: "note",
+			body: "<Note>\nA note.\n</Note>",
+			want: "> [!Note]\n> A note.",
+		},
+		{
+			name: "nested",
+			body: "<Details summary=\"More\">\n<Tip>\nInner.\n</Tip>\n</Details>",
+			want: "**More**\n\n> [!Tip]\n> Inner.",
+		},
+		{
+			name:     "unknown component keeps its children",
+			body:     "<Tabs>\n<Tab label=\"Go\">\nGo code.\n</Tab>\n</Tabs>",
+			want:     "Go code.",
+			warnings: 2,
+		},
+		{
+			name:     "unknown self-closing component",
+			body:     "Before <Chart data={data} /> after.",
+			want:     "Before  after.",
+			warnings: 1,
+		},
+		{
+			name: "type parameter",
+			body: "A List<String> of names.",
+			want: "A List<String> of names.",
+		},
+		{
+			name: "html is kept",
+			body: "<div class=\"a\">Text</div>",
+			want: "<div class=\"a\">Text</div>",
+		},
+		{
+			name: "comment",
+			body: "Before {/* a comment */}after.",
+			want: "Before after.",
+		},
+		{
+			name:    "modules",
+			body:    "import Chart from './chart'\nexport const meta = {}\n\n# Title\n",
+			modules: true,
+			want:    "\n# Title\n",
+		},
+		{
+			name: "modules are kept in markdown",
+			body: "import Chart from './chart'\n",
+			want: "import Chart from './chart'\n",
+		},
+		{
+			name: "code",
+			body: "`<Note>`\n\n```jsx\n<Callout>\nx\n</Callout>\n```\n",
+			want: "`<Note>`\n\n```jsx\n<Callout>\nx\n</Callout>\n```\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings := []string{}
+			got := ConvertMDX(tt.body, tt.modules, func(msg string) { warnings = append(warnings, msg) })
+			if got != tt.want {
+				t.Errorf("ConvertMDX(%q) = %q, want %q", tt.body, got, tt.want)
+			}
+			if len(warnings) != tt.warnings {
+				t.Errorf("ConvertMDX(%q) warnings = %q, want %d", tt.body, strings.Join(warnings, "; "), tt.warnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package util
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// maxTransclusionDepth stops notes that embed each other.
+const maxTransclusionDepth = 5
+
+var (
+	// wikilinkRe matches [[target#fragment|alias]] and ![[embed]].
+	wikilinkRe     = regexp.MustCompile(`(!?)\[\[([^\]|#]*)(#[^\]|]*)?(?:\|([^\]]*))?\]\]`)
+	embedSizeRe    = regexp.MustCompile(`^\d+(x\d+)?$`)
+	blockIDRe      = regexp.MustCompile(`(?m)[ \t]+\^[A-Za-z0-9-]+[ \t]*$`)
+	imageExtension = map[string]bool{
+		".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".webp": true,
+		".svg": true, ".bmp": true, ".avif": true,
+	}
+)
+
+// Vault converts the Obsidian syntax of the notes of a vault to plain Markdown.
+type Vault struct {
+	Root string
+	// NoteURL returns the URL of the post a note is published as, ok is false if the
+	// note is not published.
+	NoteURL func(path string) (url string, ok bool)
+	// Upload uploads an attachment, such as an image, and returns its URL.
+	Upload func(path string) (string, error)
+	// Warn reports links that cannot be converted and are replaced with their text.
+	Warn func(msg string)
+
+	// files are the slash separated paths of the files relative to Root.
+	files   []string
+	uploads map[string]string
+}
+
+// OpenVault lists the files of the vault at root, skipping hidden directories such as .obsidian.
+// Root is made absolute, so are the paths returned by Resolve.
+func OpenVault(root string) (*Vault, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("could not read vault: %w", err)
+	}
+	v := &Vault{Root: root, uploads: map[string]string{}}
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		v.files = append(v.files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not read vault: %w", err)
+	}
+	return v, nil
+}
+
+// Resolve finds the file a link from the note from points to, the way Obsidian does:
+// by path or by name, with .md being optional, preferring the folder of from and
+// then the shortest path. It returns the absolute path of the file on disk.
+func (v *Vault) Resolve(name, from string) (string, bool) {
+	name = strings.TrimSpace(filepath.ToSlash(name))
+	if name == "" {
+		return "", false
+	}
+	fromDir := ""
+	if absFrom, err := filepath.Abs(from); err == nil {
+		if rel, err := filepath.Rel(v.Root, absFrom); err == nil {
+			fromDir = path.Dir(filepath.ToSlash(rel))
+		}
+	}
+	if strings.HasPrefix(name, "./") || strings.HasPrefix(name, "../") {
+		name = path.Join(fromDir, name)
+	}
+
+	candidates := []string{}
+	for _, file := range v.files {
+		for _, n := range []string{file, strings.TrimSuffix(file, ".md")} {
+			if strings.EqualFold(n, name) || strings.HasSuffix(strings.ToLower(n), "/"+strings.ToLower(name)) {
+				candidates = append(candidates, file)
+				break
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		iLocal, jLocal := path.Dir(candidates[i]) == fromDir, path.Dir(candidates[j]) == fromDir
+		if iLocal != jLocal {
+			return iLocal
+		}
+		return len(candidates[i]) < len(candidates[j])
+	})
+	return filepath.Join(v.Root, filepath.FromSlash(candidates[0])), true
+}
+
+// Convert replaces the wikilinks and embeds of the body of the note file. Callouts
+// are the same as GitHub alerts, see ConvertCallouts.
+func (v *Vault) Convert(file, body string) (string, error) {
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return "", err
+	}
+	return v.convert(absFile, body, []string{absFile})
+}
+
+func (v *Vault) convert(file, body string, stack []string) (string, error) {
+	var firstErr error
+	body = ReplaceOutsideCode(body, wikilinkRe, func(match []string) string {
+		embed, target, fragment, alias := match[1] == "!", match[2], strings.TrimPrefix(match[3], "#"), match[4]
+		var (
+			out string
+			err error
+		)
+		if embed {
+			out, err = v.embed(file, target, fragment, alias, stack)
+		} else {
+			out, err = v.link(file, target, fragment, alias)
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		return out
+	})
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return ReplaceOutsideCode(body, blockIDRe, func([]string) string { return "" }), nil
+}
+
+func (v *Vault) link(file, target, fragment, alias string) (string, error) {
+	text := alias
+	if text == "" {
+		switch {
+		case target == "":
+			text = fragment
+		case fragment != "":
+			text = target + " > " + fragment
+		default:
+			text = target
+		}
+	}
+	if target == "" {
+		// a heading in the same note
+		return text, nil
+	}
+
+	resolved, ok := v.Resolve(target, file)
+	if !ok {
+		v.warn("%s: [[%s]] does not exist", file, target)
+		return text, nil
+	}
+	if !strings.EqualFold(filepath.Ext(resolved), ".md") {
+		url, err := v.upload(resolved)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("[%s](%s)", text, url), nil
+	}
+	url, ok := v.NoteURL(resolved)
+	if !ok {
+		v.warn("%s: [[%s]] is not published", file, target)
+		return text, nil
+	}
+	return fmt.Sprintf("[%s](%s)", text, url), nil
+}
+
+func (v *Vault) embed(file, target, fragment, alias string, stack []string) (string, error) {
+	resolved, ok := v.Resolve(target, file)
+	if !ok {
+		v.warn("%s: ![[%s]] does not exist", file, target)
+		return "", nil
+	}
+
+	ext := strings.ToLower(filepath.Ext(resolved))
+	if ext != ".md" {
+		url, err := v.upload(resolved)
+		if err != nil {
+			return "", err
+		}
+		name := strings.TrimSuffix(filepath.Base(resolved), filepath.Ext(resolved))
+		if !imageExtension[ext] {
+			return fmt.Sprintf("[%s](%s)", name, url), nil
+		}
+		if alias == "" || embedSizeRe.MatchString(alias) {
+			alias = name
+		}
+		return fmt.Sprintf("![%s](%s)", alias, url), nil
+	}
+
+	for _, f := range stack {
+		if f == resolved {
+			v.warn("%s: ![[%s]] embeds itself", file, target)
+			return "", nil
+		}
+	}
+	if len(stack) > maxTransclusionDepth {
+		v.warn("%s: ![[%s]] is nested too deeply", file, target)
+		return "", nil
+	}
+
+	doc, err := ReadDocument(resolved)
+	if err != nil {
+		return "", err
+	}
+	content := string(doc.Body)
+	if fragment != "" {
+		if content, ok = doc.Section(fragment); !ok {
+			v.warn("%s: ![[%s#%s]] does not exist", file, target, fragment)
+			return "", nil
+		}
+	}
+	content, err = v.convert(resolved, content, append(stack, resolved))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(content), nil
+}
+
+func (v *Vault) upload(file string) (string, error) {
+	if url, ok := v.uploads[file]; ok {
+		return url, nil
+	}
+	url, err := v.Upload(file)
+	if err != nil {
+		return "", fmt.Errorf("could not upload %s: %w", file, err)
+	}
+	v.uploads[file] = url
+	return url, nil
+}
+
+func (v *Vault) warn(format string, args ...any) {
+	if v.Warn != nil {
+		v.Warn(fmt.Sprintf(format, args...))
+	}
+}
+
+// Section returns the part of the body under a heading, up to the next heading of
+// the same or a higher level, or the line marked with a block ID such as ^abc123.
+func (d *Document) Section(name string) (string, bool) {
+	body := string(d.Body)
+	if id, ok := strings.CutPrefix(name, "^"); ok {
+		re := regexp.MustCompile(`(?m)^(.*?)[ \t]+\^` + regexp.QuoteMeta(id) + `[ \t]*$`)
+		match := re.FindStringSubmatch(body)
+		if match == nil {
+			return "", false
+		}
+		return match[1], true
+	}
+
+	start, level := -1, 0
+	for n := d.AST().FirstChild(); n != nil; n = n.NextSibling() {
+		heading, ok := n.(*ast.Heading)
+		if !ok || heading.Lines().Len() == 0 {
+			continue
+		}
+		line := heading.Lines().At(0)
+		if start >= 0 && heading.Level <= level {
+			end := strings.LastIndexByte(body[:line.Start], '\n') + 1
+			return body[start:end], true
+		}
+		if start < 0 && strings.EqualFold(strings.TrimSpace(nodeText(heading, d.Body)), strings.TrimSpace(name)) {
+			start, level = len(body), heading.Level
+			if i := strings.IndexByte(body[line.Stop:], '\n'); i >= 0 {
+				start = line.Stop + i + 1
+			}
+		}
+	}
+	if start < 0 {
+		return "", false
+	}
+	return body[start:], true
+}
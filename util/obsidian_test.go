@@ -0,0 +1,186 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestVault(t *testing.T) (*Vault, *[]string) {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		"notes/a.md":          "A body.\n",
+		"notes/b.md":          "# Intro\n\nIntro text.\n\n## Details\n\nDetail text.\n\n# Other\n\nOther text.\n\nA block line ^blk1\n",
+		"other/b.md":          "Other b.\n",
+		"notes/self.md":       "Self ![[self]]\n",
+		"notes/loop1.md":      "One ![[loop2]]\n",
+		"notes/loop2.md":      "Two ![[loop1]]\n",
+		"notes/private.md":    "Private.\n",
+		"notes/nested.md":     "Nested ![[b#Details]] and ![[b#^blk1]]\n",
+		"images/pic.png":      "png",
+		"files/doc.pdf":       "pdf",
+		".obsidian/hidden.md": "Hidden.\n",
+	}
+	for i := 0; i < 8; i++ {
+		files[fmt.Sprintf("deep/d%d.md", i)] = fmt.Sprintf("d%d ![[d%d]]\n", i, i+1)
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	v, err := OpenVault(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	warnings := []string{}
+	v.Warn = func(msg string) {
+		warnings = append(warnings, msg)
+	}
+	v.Upload = func(path string) (string, error) {
+		return "https://cdn.example.com/" + filepath.Base(path), nil
+	}
+	v.NoteURL = func(path string) (string, bool) {
+		name := strings.TrimSuffix(filepath.Base(path), ".md")
+		if name == "private" {
+			return "", false
+		}
+		return "https://quail.ink/list/p/" + name, true
+	}
+	return v, &warnings
+}
+
+func TestVaultResolve(t *testing.T) {
+	v, _ := newTestVault(t)
+	from := filepath.Join(v.Root, "notes", "a.md")
+
+	tests := []struct {
+		name   string
+		target string
+		want   string
+		wantOK bool
+	}{
+		{"by name", "a", "notes/a.md", true},
+		{"with extension", "a.md", "notes/a.md", true},
+		{"case insensitive", "A", "notes/a.md", true},
+		{"prefers the folder of the note", "b", "notes/b.md", true},
+		{"by path", "other/b", "other/b.md", true},
+		{"relative path", "../other/b", "other/b.md", true},
+		{"attachment", "pic.png", "images/pic.png", true},
+		{"hidden directory", "hidden", "", false},
+		{"missing", "missing", "", false},
+		{"empty", " ", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := v.Resolve(tt.target, from)
+			if ok != tt.wantOK {
+				t.Fatalf("Resolve(%q) ok = %v, want %v", tt.target, ok, tt.wantOK)
+			}
+			if want := filepath.Join(v.Root, filepath.FromSlash(tt.want)); ok && got != want {
+				t.Errorf("Resolve(%q) = %q, want %q", tt.target, got, want)
+			}
+		})
+	}
+}
+
+func TestVaultConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		body     string
+		want     string
+		warnings int
+	}{
+		{"link", "notes/a.md", "See [[b]].", "See [b](https://quail.ink/list/p/b).", 0},
+		{"link with alias", "notes/a.md", "See [[b|the note]].", "See [the note](https://quail.ink/list/p/b).", 0},
+		{"link to heading", "notes/a.md", "See [[b#Details]].", "See [b > Details](https://quail.ink/list/p/b).", 0},
+		{"heading in the same note", "notes/a.md", "See [[#Details]].", "See Details.", 0},
+		{"unpublished note", "notes/a.md", "See [[private]].", "See private.", 1},
+		{"missing note", "notes/a.md", "See [[missing]].", "See missing.", 1},
+		{"link to attachment", "notes/a.md", "Read [[doc.pdf]].", "Read [doc.pdf](https://cdn.example.com/doc.pdf).", 0},
+		{"image", "notes/a.md", "![[pic.png]]", "![pic](https://cdn.example.com/pic.png)", 0},
+		{"image with size", "notes/a.md", "![[pic.png|100x200]]", "![pic](https://cdn.example.com/pic.png)", 0},
+		{"image with alt", "notes/a.md", "![[pic.png|A picture]]", "![A picture](https://cdn.example.com/pic.png)", 0},
+		{"embedded file", "notes/a.md", "![[doc.pdf]]", "[doc](https://cdn.example.com/doc.pdf)", 0},
+		{"transclusion", "notes/b.md", "![[a]]", "A body.", 0},
+		{"section", "notes/a.md", "![[b#Intro]]", "Intro text.\n\n## Details\n\nDetail text.", 0},
+		{"block id", "notes/a.md", "![[b#^blk1]]", "A block line", 0},
+		{"missing section", "notes/a.md", "![[b#Nope]]", "", 1},
+		{"nested", "notes/a.md", "![[nested]]", "Nested Detail text. and A block line", 0},
+		{"self embed", "notes/self.md", "Self ![[self]]", "Self ", 1},
+		{"embed loop", "notes/loop1.md", "One ![[loop2]]", "One Two", 1},
+		{"depth limit", "deep/d0.md", "d0 ![[d1]]", "d0 d1 d2 d3 d4 d5", 1},
+		{"block ids are removed", "notes/a.md", "Line ^abc\n", "Line\n", 0},
+		{"code is kept", "notes/a.md", "`[[b]]`", "`[[b]]`", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, warnings := newTestVault(t)
+			got, err := v.Convert(filepath.Join(v.Root, filepath.FromSlash(tt.file)), tt.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Convert(%q) = %q, want %q", tt.body, got, tt.want)
+			}
+			if len(*warnings) != tt.warnings {
+				t.Errorf("Convert(%q) warnings = %q, want %d", tt.body, *warnings, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestVaultConvertRelativeFile(t *testing.T) {
+	v, warnings := newTestVault(t)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := filepath.Rel(wd, filepath.Join(v.Root, "notes", "self.md"))
+	if err != nil {
+		t.Skip("temp dir is not relative to the working directory")
+	}
+	got, err := v.Convert(file, "Self ![[self]] [[b]]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Self  [b](https://quail.ink/list/p/b)"; got != want {
+		t.Errorf("Convert = %q, want %q", got, want)
+	}
+	if len(*warnings) != 1 {
+		t.Errorf("warnings = %q, want the self embed", *warnings)
+	}
+}
+
+func TestDocumentSection(t *testing.T) {
+	doc := &Document{Body: []byte("# Intro\n\nIntro text.\n\n## Details\n\nDetail text.\n\n# Other\n\nOther text.\n\nA block line ^blk1\n")}
+	tests := []struct {
+		name   string
+		want   string
+		wantOK bool
+	}{
+		{"Intro", "\nIntro text.\n\n## Details\n\nDetail text.\n\n", true},
+		{"details", "\nDetail text.\n\n", true},
+		{"Other", "\nOther text.\n\nA block line ^blk1\n", true},
+		{"^blk1", "A block line", true},
+		{"^missing", "", false},
+		{"Missing", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := doc.Section(tt.name)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("Section(%q) = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
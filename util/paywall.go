@@ -0,0 +1,38 @@
+package util
+
+import "strings"
+
+// DefaultPaywallMarker separates the free part of a post from the part only
+// visible to paid subscribers. It can be changed with `post.paywall_marker`.
+const DefaultPaywallMarker = "<!-- paywall -->"
+
+// SplitPaidContent splits content at the first line consisting of the paywall marker
+// into the free content and the paid content. Markers in code blocks are ignored.
+// If there is no marker, paid is empty.
+func SplitPaidContent(content, marker string) (free, paid string) {
+	var ranges [][2]int
+	offset := 0
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if strings.TrimSpace(line) == marker {
+			if ranges == nil {
+				ranges = codeRanges(content)
+			}
+			indent := len(line) - len(strings.TrimLeft(line, " \t"))
+			if !inRanges(ranges, offset+indent) {
+				free = strings.TrimRight(content[:offset], "\n") + "\n"
+				paid = strings.TrimLeft(content[offset+len(line):], "\n")
+				return free, paid
+			}
+		}
+		offset += len(line)
+	}
+	return content, ""
+}
+
+// JoinPaidContent is the reverse of SplitPaidContent.
+func JoinPaidContent(free, paid, marker string) string {
+	if paid == "" {
+		return free
+	}
+	return strings.TrimRight(free, "\n") + "\n\n" + marker + "\n\n" + paid
+}
@@ -0,0 +1,70 @@
+package util
+
+import "testing"
+
+func TestSplitPaidContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		free    string
+		paid    string
+	}{
+		{
+			name:    "no marker",
+			content: "free\n",
+			free:    "free\n",
+		},
+		{
+			name:    "marker",
+			content: "free\n\n<!-- paywall -->\n\npaid\n",
+			free:    "free\n",
+			paid:    "paid\n",
+		},
+		{
+			name:    "indented marker",
+			content: "free\n  <!-- paywall -->  \npaid\n",
+			free:    "free\n",
+			paid:    "paid\n",
+		},
+		{
+			name:    "marker in text",
+			content: "free <!-- paywall --> text\n",
+			free:    "free <!-- paywall --> text\n",
+		},
+		{
+			name:    "marker in fenced code",
+			content: "free\n\n```html\n<!-- paywall -->\n```\n",
+			free:    "free\n\n```html\n<!-- paywall -->\n```\n",
+		},
+		{
+			name:    "marker in indented code",
+			content: "free\n\n    <!-- paywall -->\n",
+			free:    "free\n\n    <!-- paywall -->\n",
+		},
+		{
+			name:    "marker after fenced code",
+			content: "```\n<!-- paywall -->\n```\n\n<!-- paywall -->\npaid\n",
+			free:    "```\n<!-- paywall -->\n```\n",
+			paid:    "paid\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			free, paid := SplitPaidContent(tt.content, DefaultPaywallMarker)
+			if free != tt.free || paid != tt.paid {
+				t.Errorf("SplitPaidContent(%q) = %q, %q, want %q, %q", tt.content, free, paid, tt.free, tt.paid)
+			}
+		})
+	}
+}
+
+func TestJoinPaidContent(t *testing.T) {
+	content := "free\n\n<!-- paywall -->\n\npaid\n"
+	free, paid := SplitPaidContent(content, DefaultPaywallMarker)
+	if got := JoinPaidContent(free, paid, DefaultPaywallMarker); got != content {
+		t.Errorf("JoinPaidContent() = %q, want %q", got, content)
+	}
+	if got := JoinPaidContent("free\n", "", DefaultPaywallMarker); got != "free\n" {
+		t.Errorf("JoinPaidContent() without paid content = %q, want %q", got, "free\n")
+	}
+}
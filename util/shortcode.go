@@ -0,0 +1,111 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// shortcodeRe matches {{< name args >}}, {{% name args %}}, their closing tags
+	// {{< /name >}} and self-closing tags {{< name />}}.
+	shortcodeRe = regexp.MustCompile(`\{\{([<%])\s*(/?)\s*([\w.-]+)((?:[^>%"]|"(?:[^"\\]|\\.)*"|[>%][^}])*?)\s*(/?)\s*[>%]\}\}`)
+	// shortcodeCommentRe matches escaped shortcodes, {{</* name */>}} is written as {{< name >}}.
+	shortcodeCommentRe = regexp.MustCompile(`\{\{([<%])/\*\s*(.*?)\s*\*/([>%])\}\}`)
+	shortcodeArgRe     = regexp.MustCompile("(?:([\\w-]+)=)?(?:\"((?:[^\"\\\\]|\\\\.)*)\"|`([^`]*)`|(\\S+))")
+)
+
+// ConvertShortcodes replaces common Hugo shortcodes with Markdown Quail can show:
+// videos and posts of other sites become their URL on a line of their own, which
+// Quail can embed, figures become images, highlight becomes a fenced code block and
+// ref and relref become the path of the linked file. Unknown shortcodes are removed,
+// keeping their content, and reported to warn. params are the values of param.
+func ConvertShortcodes(body string, params map[string]any, warn func(string)) string {
+	body = convertElements(body, findShortcodes, func(e element, inner string, paired bool) string {
+		switch e.name {
+		case "youtube":
+			return "https://www.youtube.com/watch?v=" + e.arg("id", 0)
+		case "vimeo":
+			return "https://vimeo.com/" + e.arg("id", 0)
+		case "instagram":
+			return "https://www.instagram.com/p/" + e.arg("id", 0) + "/"
+		case "x", "twitter", "tweet":
+			user, id := e.arg("user", 0), e.arg("id", 1)
+			if id == "" {
+				// the old form only has the id
+				user, id = "i", user
+			}
+			return "https://x.com/" + user + "/status/" + id
+		case "gist":
+			return "https://gist.github.com/" + e.arg("user", 0) + "/" + e.arg("id", 1)
+		case "figure":
+			caption := e.arg("caption", -1)
+			if caption == "" {
+				caption = e.arg("title", -1)
+			}
+			return formatFigure(e.arg("src", 0), e.arg("alt", -1), "", caption, e.arg("link", -1))
+		case "highlight":
+			code := strings.Trim(inner, "\n")
+			fence := "```"
+			for strings.Contains(code, fence) {
+				fence += "`"
+			}
+			return fence + e.arg("lang", 0) + "\n" + code + "\n" + fence
+		case "ref", "relref":
+			return e.arg("path", 0)
+		case "param":
+			name := e.arg("name", 0)
+			value, ok := params[name]
+			if !ok {
+				warn(fmt.Sprintf("shortcode param: %s is not in the frontmatter", name))
+				return ""
+			}
+			return fmt.Sprint(value)
+		case "details":
+			return formatDetails(e.arg("summary", -1), inner)
+		case "notice", "admonition", "alert", "callout", "hint":
+			kind := e.arg("type", 0)
+			if kind == "" {
+				kind = "note"
+			}
+			return formatCallout(kind, e.arg("title", 1), inner)
+		}
+		warn(fmt.Sprintf("unknown shortcode %s removed", e.name))
+		return strings.Trim(inner, "\n")
+	})
+	return ReplaceOutsideCode(body, shortcodeCommentRe, func(match []string) string {
+		return "{{" + match[1] + " " + match[2] + " " + match[3] + "}}"
+	})
+}
+
+func findShortcodes(body string) []element {
+	ranges := codeRanges(body)
+	elements := []element{}
+	for _, loc := range shortcodeRe.FindAllStringSubmatchIndex(body, -1) {
+		if inRanges(ranges, loc[0]) {
+			continue
+		}
+		e := element{
+			start:       loc[0],
+			end:         loc[1],
+			raw:         body[loc[0]:loc[1]],
+			name:        body[loc[6]:loc[7]],
+			args:        map[string]string{},
+			closing:     loc[5] > loc[4],
+			selfClosing: loc[11] > loc[10],
+		}
+		for _, arg := range shortcodeArgRe.FindAllStringSubmatch(body[loc[8]:loc[9]], -1) {
+			value := arg[2] + arg[3] + arg[4]
+			if arg[2] != "" {
+				value = strings.ReplaceAll(value, `\"`, `"`)
+			}
+			if arg[1] != "" {
+				e.args[arg[1]] = value
+			} else {
+				e.positional = append(e.positional, value)
+			}
+		}
+		elements = append(elements, e)
+	}
+	return elements
+}
@@ -0,0 +1,126 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConvertShortcodes(t *testing.T) {
+	params := map[string]any{"version": "1.2.3"}
+	tests := []struct {
+		name     string
+		body     string
+		want     string
+		warnings int
+	}{
+		{
+			name: "youtube",
+			body: `{{< youtube dQw4w9WgXcQ >}}`,
+			want: "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+		},
+		{
+			name: "named argument",
+			body: `{{< youtube id="dQw4w9WgXcQ" >}}`,
+			want: "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+		},
+		{
+			name: "tweet with user",
+			body: `{{< x user="golang" id="123" >}}`,
+			want: "https://x.com/golang/status/123",
+		},
+		{
+			name: "tweet with id only",
+			body: `{{< tweet 123 >}}`,
+			want: "https://x.com/i/status/123",
+		},
+		{
+			name: "gist",
+			body: `{{< gist spf13 7896402 >}}`,
+			want: "https://gist.github.com/spf13/7896402",
+		},
+		{
+			name: "figure",
+			body: `{{< figure src="/a.png" alt="An \"A\"" caption="Caption" link="https://example.com" >}}`,
+			want: "[![An \"A\"](/a.png)](https://example.com)\n\n*Caption*",
+		},
+		{
+			name: "self-closing",
+			body: `{{< figure src="/a.png" />}}`,
+			want: "![](/a.png)",
+		},
+		{
+			name: "highlight",
+			body: "{{< highlight go >}}\nfmt.Println(\"{{ . }}\")\n{{< /highlight >}}",
+			want: "```go\nfmt.Println(\"{{ . }}\")\n```",
+		},
+		{
+			name: "ref",
+			body: `See [the post]({{< ref "posts/other.md" >}}).`,
+			want: "See [the post](posts/other.md).",
+		},
+		{
+			name: "param",
+			body: `Version {{< param version >}}.`,
+			want: "Version 1.2.3.",
+		},
+		{
+			name:     "missing param",
+			body:     `Version {{< param missing >}}.`,
+			want:     "Version .",
+			warnings: 1,
+		},
+		{
+			name: "notice with markdown",
+			body: "{{% notice warning \"Careful\" %}}\nDo **not** do this.\n{{% /notice %}}",
+			want: "> [!warning] Careful\n> Do **not** do this.",
+		},
+		{
+			name: "nested",
+			body: "{{< details summary=\"More\" >}}\n{{< notice tip >}}\nInner.\n{{< /notice >}}\n{{< /details >}}",
+			want: "**More**\n\n> [!tip]\n> Inner.",
+		},
+		{
+			name: "nested with the same name",
+			body: "{{< details >}}\nA\n{{< details >}}\nB\n{{< /details >}}\nC\n{{< /details >}}",
+			want: "A\nB\nC",
+		},
+		{
+			name:     "unknown shortcode keeps its content",
+			body:     "{{< custom >}}\nKept.\n{{< /custom >}}",
+			want:     "Kept.",
+			warnings: 1,
+		},
+		{
+			name: "stray closing tag",
+			body: "Text {{< /notice >}} here.",
+			want: "Text  here.",
+		},
+		{
+			name: "escaped",
+			body: "Write {{</* youtube id */>}} or {{%/* notice */%}}.",
+			want: "Write {{< youtube id >}} or {{% notice %}}.",
+		},
+		{
+			name: "code span",
+			body: "Use `{{< youtube id >}}` to embed.",
+			want: "Use `{{< youtube id >}}` to embed.",
+		},
+		{
+			name: "code block",
+			body: "```\n{{< youtube id >}}\n{{</* youtube id */>}}\n```\n",
+			want: "```\n{{< youtube id >}}\n{{</* youtube id */>}}\n```\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings := []string{}
+			got := ConvertShortcodes(tt.body, params, func(msg string) { warnings = append(warnings, msg) })
+			if got != tt.want {
+				t.Errorf("ConvertShortcodes(%q) = %q, want %q", tt.body, got, tt.want)
+			}
+			if len(warnings) != tt.warnings {
+				t.Errorf("ConvertShortcodes(%q) warnings = %q, want %d", tt.body, strings.Join(warnings, "; "), tt.warnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package util
+
+import (
+	"net/url"
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/mozillazg/go-pinyin"
+	"golang.org/x/text/unicode/norm"
+)
+
+// DefaultSlugMaxLength is the slug length used when `post.slug_max_length` is not set.
+const DefaultSlugMaxLength = 60
+
+var slugStopwords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "or": true, "but": true,
+	"of": true, "to": true, "in": true, "on": true, "at": true, "by": true,
+	"for": true, "with": true, "from": true, "as": true, "is": true, "are": true,
+	"was": true, "were": true, "be": true, "it": true, "its": true, "this": true,
+	"that": true,
+}
+
+var pinyinArgs = pinyin.NewArgs()
+
+// romaji of hiragana, katakana are mapped to hiragana first
+var kanaRomaji = map[string]string{
+	"あ": "a", "い": "i", "う": "u", "え": "e", "お": "o",
+	"か": "ka", "き": "ki", "く": "ku", "け": "ke", "こ": "ko",
+	"さ": "sa", "し": "shi", "す": "su", "せ": "se", "そ": "so",
+	"た": "ta", "ち": "chi", "つ": "tsu", "て": "te", "と": "to",
+	"な": "na", "に": "ni", "ぬ": "nu", "ね": "ne", "の": "no",
+	"は": "ha", "ひ": "hi", "ふ": "fu", "へ": "he", "ほ": "ho",
+	"ま": "ma", "み": "mi", "む": "mu", "め": "me", "も": "mo",
+	"や": "ya", "ゆ": "yu", "よ": "yo",
+	"ら": "ra", "り": "ri", "る": "ru", "れ": "re", "ろ": "ro",
+	"わ": "wa", "ゐ": "i", "ゑ": "e", "を": "o", "ん": "n",
+	"が": "ga", "ぎ": "gi", "ぐ": "gu", "げ": "ge", "ご": "go",
+	"ざ": "za", "じ": "ji", "ず": "zu", "ぜ": "ze", "ぞ": "zo",
+	"だ": "da", "ぢ": "ji", "づ": "zu", "で": "de", "ど": "do",
+	"ば": "ba", "び": "bi", "ぶ": "bu", "べ": "be", "ぼ": "bo",
+	"ぱ": "pa", "ぴ": "pi", "ぷ": "pu", "ぺ": "pe", "ぽ": "po",
+	"ぁ": "a", "ぃ": "i", "ぅ": "u", "ぇ": "e", "ぉ": "o", "ゔ": "vu",
+	"きゃ": "kya", "きゅ": "kyu", "きょ": "kyo",
+	"しゃ": "sha", "しゅ": "shu", "しょ": "sho",
+	"ちゃ": "cha", "ちゅ": "chu", "ちょ": "cho",
+	"にゃ": "nya", "にゅ": "nyu", "にょ": "nyo",
+	"ひゃ": "hya", "ひゅ": "hyu", "ひょ": "hyo",
+	"みゃ": "mya", "みゅ": "myu", "みょ": "myo",
+	"りゃ": "rya", "りゅ": "ryu", "りょ": "ryo",
+	"ぎゃ": "gya", "ぎゅ": "gyu", "ぎょ": "gyo",
+	"じゃ": "ja", "じゅ": "ju", "じょ": "jo",
+	"びゃ": "bya", "びゅ": "byu", "びょ": "byo",
+	"ぴゃ": "pya", "ぴゅ": "pyu", "ぴょ": "pyo",
+	"ふぁ": "fa", "ふぃ": "fi", "ふぇ": "fe", "ふぉ": "fo",
+	"てぃ": "ti", "でぃ": "di", "うぃ": "wi", "うぇ": "we",
+}
+
+// Slugify turns s into a URL slug of lowercase ASCII words joined by hyphens.
+// Accents are removed, Chinese characters are transliterated to pinyin and
+// kana to romaji. Kanji are transliterated with their Chinese reading.
+func Slugify(s string) string {
+	return strings.Join(slugWords(s), "-")
+}
+
+// SlugifyTitle is like Slugify, but also drops English stopwords and limits the
+// slug to maxLength characters without cutting words, so it suits post titles.
+func SlugifyTitle(title string, maxLength int) string {
+	words := slugWords(title)
+
+	kept := []string{}
+	for _, word := range words {
+		if !slugStopwords[word] {
+			kept = append(kept, word)
+		}
+	}
+	if len(kept) > 0 {
+		words = kept
+	}
+
+	slug := ""
+	for _, word := range words {
+		next := word
+		if slug != "" {
+			next = slug + "-" + word
+		}
+		if maxLength > 0 && len(next) > maxLength {
+			if slug == "" {
+				slug = word[:maxLength]
+			}
+			break
+		}
+		slug = next
+	}
+	return slug
+}
+
+func slugWords(s string) []string {
+	words := []string{}
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+
+	// decompose accented letters so the combining marks can be dropped
+	runes := []rune(norm.NFKD.String(strings.ToLower(s)))
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			word.WriteRune(r)
+		case unicode.Is(unicode.Mn, r):
+		case unicode.Is(unicode.Han, r):
+			flush()
+			if py := pinyin.SinglePinyin(r, pinyinArgs); len(py) > 0 {
+				words = append(words, py[0])
+			}
+		case isKana(r):
+			n := 1
+			for i+n < len(runes) && (isKana(runes[i+n]) || unicode.Is(unicode.Mn, runes[i+n])) {
+				n++
+			}
+			flush()
+			if romaji := kanaToRomaji(norm.NFKC.String(string(runes[i : i+n]))); romaji != "" {
+				words = append(words, romaji)
+			}
+			i += n - 1
+		default:
+			flush()
+		}
+	}
+	flush()
+	return words
+}
+
+func isKana(r rune) bool {
+	return unicode.In(r, unicode.Hiragana, unicode.Katakana) || r == 'ー'
+}
+
+func kanaToRomaji(kana string) string {
+	runes := []rune(kana)
+	for i, r := range runes {
+		// katakana to hiragana
+		if r >= 'ァ' && r <= 'ヶ' {
+			runes[i] = r - 0x60
+		}
+	}
+
+	var b strings.Builder
+	double := false
+	last := ""
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r == 'っ' {
+			double = true
+			continue
+		}
+		if r == 'ー' {
+			if last != "" {
+				b.WriteString(last[len(last)-1:])
+			}
+			continue
+		}
+		romaji := ""
+		if i+1 < len(runes) {
+			romaji = kanaRomaji[string(runes[i:i+2])]
+			if romaji != "" {
+				i++
+			}
+		}
+		if romaji == "" {
+			romaji = kanaRomaji[string(r)]
+		}
+		if romaji == "" {
+			continue
+		}
+		if double {
+			b.WriteByte(romaji[0])
+			double = false
+		}
+		b.WriteString(romaji)
+		last = romaji
+	}
+	return b.String()
+}
+
+// SlugFromURL derives a slug from the last path segment of a post URL,
+// e.g. https://example.com/2023/05/hello-world.html -> hello-world.
+func SlugFromURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	base := path.Base(strings.TrimSuffix(u.Path, "/"))
+	if base == "." || base == "/" {
+		return ""
+	}
+	base = strings.TrimSuffix(base, path.Ext(base))
+	return Slugify(base)
+}
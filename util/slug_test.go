@@ -0,0 +1,43 @@
+package util
+
+import "testing"
+
+func TestSlugifyTitle(t *testing.T) {
+	tests := []struct {
+		name      string
+		title     string
+		maxLength int
+		want      string
+	}{
+		{"punctuation", "Hello, World!", 60, "hello-world"},
+		{"stopwords", "The Art of Go", 60, "art-go"},
+		{"only stopwords", "the", 60, "the"},
+		{"accents", "Café déjà vu", 60, "cafe-deja-vu"},
+		{"chinese", "你好世界", 60, "ni-hao-shi-jie"},
+		{"mixed", "Go 语言入门", 60, "go-yu-yan-ru-men"},
+		{"hiragana", "こんにちは", 60, "konnichiha"},
+		{"katakana", "カタカナ", 60, "katakana"},
+		{"contracted kana", "きょう", 60, "kyou"},
+		{"max length", "a very long title that goes on and on for many words beyond", 20, "very-long-title-goes"},
+		{"no words", "  --  ", 60, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SlugifyTitle(tt.title, tt.maxLength); got != tt.want {
+				t.Errorf("SlugifyTitle(%q, %d) = %q, want %q", tt.title, tt.maxLength, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	if got, want := Slugify("The Art of Go"), "the-art-of-go"; got != want {
+		t.Errorf("Slugify() = %q, want %q", got, want)
+	}
+}
+
+func TestSlugFromURL(t *testing.T) {
+	if got, want := SlugFromURL("https://example.com/2023/05/hello-world.html"), "hello-world"; got != want {
+		t.Errorf("SlugFromURL() = %q, want %q", got, want)
+	}
+}
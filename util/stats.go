@@ -0,0 +1,128 @@
+package util
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+)
+
+const (
+	// reading speeds for Stats.ReadingMinutes
+	WordsPerMinute         = 200
+	CJKCharactersPerMinute = 400
+
+	// EmailClipSize is the size above which Gmail clips an email.
+	EmailClipSize = 102 * 1024
+	// emailLayoutSize approximates the layout Quail adds around a post in an email.
+	emailLayoutSize = 8 * 1024
+)
+
+type (
+	Heading struct {
+		Level int    `json:"level"`
+		Text  string `json:"text"`
+		Line  int    `json:"line"`
+	}
+
+	Stats struct {
+		// Words counts the Latin words and the CJK characters, which are words of their own.
+		Words         int `json:"words"`
+		LatinWords    int `json:"latin_words"`
+		CJKCharacters int `json:"cjk_characters"`
+		// Characters counts the characters that are not spaces.
+		Characters     int       `json:"characters"`
+		ReadingMinutes int       `json:"reading_minutes"`
+		Headings       []Heading `json:"headings"`
+		Images         int       `json:"images"`
+		Links          int       `json:"links"`
+		CodeBlocks     int       `json:"code_blocks"`
+		// EmailSize estimates the size of the email in bytes when the post is delivered.
+		EmailSize    int  `json:"email_size"`
+		EmailClipped bool `json:"email_clipped"`
+	}
+)
+
+var htmlRenderer = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// Stats counts the words, headings, images, links and code blocks of the body. Code
+// blocks do not count as words.
+func (d *Document) Stats() Stats {
+	stats := Stats{Headings: []Heading{}}
+	inWord := false
+	countText := func(text []byte) {
+		for _, r := range string(text) {
+			switch {
+			case IsWide(r) && unicode.IsLetter(r):
+				stats.CJKCharacters++
+				inWord = false
+			case unicode.IsLetter(r) || unicode.IsDigit(r):
+				if !inWord {
+					stats.LatinWords++
+				}
+				inWord = true
+			case r == '\'' || r == '’' || r == '-':
+				// part of a word, as in don't and well-known
+			default:
+				inWord = false
+			}
+			if !unicode.IsSpace(r) {
+				stats.Characters++
+			}
+		}
+	}
+
+	ast.Walk(d.AST(), func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			stats.CodeBlocks++
+			return ast.WalkSkipChildren, nil
+		case *ast.HTMLBlock:
+			return ast.WalkSkipChildren, nil
+		case *ast.Heading:
+			if node.Lines().Len() > 0 {
+				line, _ := d.Position(node.Lines().At(0).Start)
+				stats.Headings = append(stats.Headings, Heading{
+					Level: node.Level,
+					Text:  strings.TrimSpace(nodeText(node, d.Body)),
+					Line:  line,
+				})
+			}
+		case *ast.Text:
+			countText(node.Segment.Value(d.Body))
+			if node.SoftLineBreak() || node.HardLineBreak() {
+				inWord = false
+			}
+		}
+		if n.Type() == ast.TypeBlock {
+			inWord = false
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, link := range d.Links() {
+		if link.Image {
+			stats.Images++
+		} else {
+			stats.Links++
+		}
+	}
+
+	stats.Words = stats.LatinWords + stats.CJKCharacters
+	minutes := float64(stats.LatinWords)/WordsPerMinute + float64(stats.CJKCharacters)/CJKCharactersPerMinute
+	stats.ReadingMinutes = int(math.Ceil(minutes))
+
+	var html bytes.Buffer
+	if err := htmlRenderer.Convert(d.Body, &html); err == nil {
+		stats.EmailSize = html.Len() + emailLayoutSize
+	}
+	stats.EmailClipped = stats.EmailSize > EmailClipSize
+	return stats
+}
@@ -0,0 +1,172 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	SummaryModeAuto = "auto"
+	SummaryModeKeep = "keep"
+	SummaryModeNone = "none"
+
+	// DefaultSummaryLength is the summary width used when `post.summary_length` is not set.
+	DefaultSummaryLength = 200
+)
+
+var (
+	excerptMarkerRe = regexp.MustCompile(`(?m)^[ \t]*<!--\s*more\s*-->[ \t]*\n?`)
+
+	mdImageRe      = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLinkRe       = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	mdRefLinkRe    = regexp.MustCompile(`\[([^\]]*)\]\[[^\]]*\]`)
+	mdInlineCodeRe = regexp.MustCompile("`([^`]*)`")
+	mdEmphasisRes  = []*regexp.Regexp{
+		regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*`),
+		regexp.MustCompile(`__(\S(?:.*?\S)?)__`),
+		regexp.MustCompile(`~~(\S(?:.*?\S)?)~~`),
+		regexp.MustCompile(`\*(\S(?:[^*]*?\S)?)\*`),
+		regexp.MustCompile(`\b_(\S(?:[^_]*?\S)?)_\b`),
+	}
+	htmlTagRe     = regexp.MustCompile(`<[^>]+>`)
+	mdBlockPrefix = regexp.MustCompile(`^\s*(#{1,6}\s+|>\s?|[-*+]\s+|\d+[.)]\s+)`)
+)
+
+// SplitExcerpt looks for a `<!--more-->` marker outside code. If there is one, it returns
+// the content before the marker as the excerpt and the content with the marker removed.
+func SplitExcerpt(content string) (excerpt, rest string, ok bool) {
+	var ranges [][2]int
+	for _, loc := range excerptMarkerRe.FindAllStringIndex(content, -1) {
+		if ranges == nil {
+			ranges = codeRanges(content)
+		}
+		start := loc[0] + len(content[loc[0]:loc[1]]) - len(strings.TrimLeft(content[loc[0]:loc[1]], " \t"))
+		if !inRanges(ranges, start) {
+			return content[:loc[0]], content[:loc[0]] + content[loc[1]:], true
+		}
+	}
+	return "", content, false
+}
+
+// Summarize builds a plain text summary of Markdown content: the excerpt before a
+// `<!--more-->` marker, or else the first paragraph, truncated to length.
+func Summarize(content string, length int) string {
+	if excerpt, _, ok := SplitExcerpt(content); ok {
+		return Truncate(StripMarkdown(excerpt), length)
+	}
+	return Truncate(StripMarkdown(firstParagraph(content)), length)
+}
+
+// firstParagraph returns the first block of text lines, skipping headings,
+// fenced code, HTML comments, images and thematic breaks.
+func firstParagraph(content string) string {
+	lines := []string{}
+	inFence := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if trimmed == "" {
+			if len(lines) > 0 {
+				break
+			}
+			continue
+		}
+		if len(lines) == 0 {
+			if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "<!--") ||
+				strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t") ||
+				strings.Trim(trimmed, "-*_ ") == "" ||
+				strings.TrimSpace(mdImageRe.ReplaceAllString(trimmed, "")) == "" {
+				continue
+			}
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// StripMarkdown removes the most common Markdown and HTML syntax from text,
+// keeping link texts and image alt texts, and collapses whitespace.
+func StripMarkdown(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = mdBlockPrefix.ReplaceAllString(line, "")
+	}
+	text = strings.Join(lines, " ")
+	text = mdImageRe.ReplaceAllString(text, "$1")
+	text = mdLinkRe.ReplaceAllString(text, "$1")
+	text = mdRefLinkRe.ReplaceAllString(text, "$1")
+	text = mdInlineCodeRe.ReplaceAllString(text, "$1")
+	for _, re := range mdEmphasisRes {
+		text = re.ReplaceAllString(text, "$1")
+	}
+	text = htmlTagRe.ReplaceAllString(text, "")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// IsWide reports whether r is a CJK character or punctuation, which takes two columns.
+func IsWide(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) || r == 'ー' ||
+		(r >= 0x3000 && r <= 0x303F) || (r >= 0xFF00 && r <= 0xFFEF)
+}
+
+func runeWidth(r rune) int {
+	if IsWide(r) {
+		return 2
+	}
+	return 1
+}
+
+// Width returns the number of columns of text, where CJK characters count as two.
+func Width(text string) int {
+	width := 0
+	for _, r := range text {
+		width += runeWidth(r)
+	}
+	return width
+}
+
+// Truncate shortens text to at most width columns, including the ellipsis it
+// appends, where CJK characters count as two columns. Latin words are not cut
+// in half unless a single word is longer than the whole width.
+func Truncate(text string, width int) string {
+	if width <= 0 || Width(text) <= width {
+		return text
+	}
+
+	// one column is left for the ellipsis
+	width--
+	used := 0
+	lastBreak := -1
+	for i, r := range text {
+		w := runeWidth(r)
+		if used+w > width {
+			cut := i
+			// do not cut a latin word in half, CJK text can be cut anywhere
+			if !IsWide(r) && lastBreak > 0 {
+				prev, _ := utf8.DecodeLastRuneInString(text[:i])
+				if !unicode.IsSpace(r) && !unicode.IsSpace(prev) && !IsWide(prev) {
+					cut = lastBreak
+				}
+			}
+			return strings.TrimRightFunc(text[:cut], func(r rune) bool {
+				return unicode.IsSpace(r) || unicode.IsPunct(r)
+			}) + "…"
+		}
+		used += w
+		if unicode.IsSpace(r) || IsWide(r) {
+			lastBreak = i + utf8.RuneLen(r)
+			if unicode.IsSpace(r) {
+				lastBreak = i
+			}
+		}
+	}
+	return text
+}
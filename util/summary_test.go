@@ -0,0 +1,104 @@
+package util
+
+import "testing"
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		width int
+		want  string
+	}{
+		{"fits", "hello world", 11, "hello world"},
+		{"no width", "hello world", 0, "hello world"},
+		{"word boundary", "hello world again", 13, "hello world…"},
+		{"ellipsis column", "hello world", 10, "hello…"},
+		{"long word", "abcdefghij", 5, "abcd…"},
+		{"trailing punctuation", "hello, world", 8, "hello…"},
+		{"cjk", "你好世界你好", 9, "你好世界…"},
+		{"cjk fits", "你好世界", 8, "你好世界"},
+		{"mixed", "Go 语言很好", 8, "Go 语言…"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Truncate(tt.text, tt.width)
+			if got != tt.want {
+				t.Errorf("Truncate(%q, %d) = %q, want %q", tt.text, tt.width, got, tt.want)
+			}
+			if tt.width > 0 && Width(got) > tt.width {
+				t.Errorf("Truncate(%q, %d) is %d columns wide", tt.text, tt.width, Width(got))
+			}
+		})
+	}
+}
+
+func TestSplitExcerpt(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		excerpt string
+		rest    string
+		ok      bool
+	}{
+		{
+			name:    "no marker",
+			content: "one\n\ntwo\n",
+			rest:    "one\n\ntwo\n",
+		},
+		{
+			name:    "marker",
+			content: "one\n<!--more-->\ntwo\n",
+			excerpt: "one\n",
+			rest:    "one\ntwo\n",
+			ok:      true,
+		},
+		{
+			name:    "marker with spaces",
+			content: "one\n  <!-- more -->\ntwo\n",
+			excerpt: "one\n",
+			rest:    "one\ntwo\n",
+			ok:      true,
+		},
+		{
+			name:    "marker in fenced code",
+			content: "one\n\n```\n<!--more-->\n```\n",
+			rest:    "one\n\n```\n<!--more-->\n```\n",
+		},
+		{
+			name:    "marker after fenced code",
+			content: "```\n<!--more-->\n```\n<!--more-->\ntwo\n",
+			excerpt: "```\n<!--more-->\n```\n",
+			rest:    "```\n<!--more-->\n```\ntwo\n",
+			ok:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			excerpt, rest, ok := SplitExcerpt(tt.content)
+			if excerpt != tt.excerpt || rest != tt.rest || ok != tt.ok {
+				t.Errorf("SplitExcerpt(%q) = %q, %q, %v, want %q, %q, %v", tt.content, excerpt, rest, ok, tt.excerpt, tt.rest, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"first paragraph", "# Title\n\nThe **first** [paragraph](https://example.com).\n\nSecond.\n", "The first paragraph."},
+		{"excerpt", "One.\n\nTwo.\n<!--more-->\nThree.\n", "One. Two."},
+		{"skips code", "```\ncode\n```\n\nText.\n", "Text."},
+		{"no paragraph", "![img](a.png)\n", ""},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Summarize(tt.content, DefaultSummaryLength); got != tt.want {
+				t.Errorf("Summarize(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
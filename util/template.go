@@ -0,0 +1,86 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// maskedShortcodeRe matches the placeholders of the shortcodes RenderTemplate masks.
+var maskedShortcodeRe = regexp.MustCompile("\x00(\\d+)\x00")
+
+// TemplateFuncs are the helper functions available in post templates, besides the
+// builtin functions of text/template.
+var TemplateFuncs = template.FuncMap{
+	"now": time.Now,
+	// date formats a time, a *time.Time or a datetime string with a Go layout
+	"date": func(layout string, t any) (string, error) {
+		switch v := t.(type) {
+		case time.Time:
+			return v.Format(layout), nil
+		case *time.Time:
+			if v == nil {
+				return "", nil
+			}
+			return v.Format(layout), nil
+		case string:
+			parsed, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return "", err
+			}
+			return parsed.Format(layout), nil
+		}
+		return "", fmt.Errorf("date: unsupported value %v", t)
+	},
+	"slugify": func(s string) string {
+		return SlugifyTitle(s, DefaultSlugMaxLength)
+	},
+	"lower":    strings.ToLower,
+	"upper":    strings.ToUpper,
+	"trim":     strings.TrimSpace,
+	"replace":  func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+	"split":    func(sep, s string) []string { return strings.Split(s, sep) },
+	"join":     func(sep string, items []string) string { return strings.Join(items, sep) },
+	"contains": func(substr, s string) bool { return strings.Contains(s, substr) },
+	"env":      os.Getenv,
+	// default returns def if value is empty, e.g. {{ .Params.author | default "Anonymous" }}
+	"default": func(def, value any) any {
+		if value == nil || value == "" || value == false || value == 0 {
+			return def
+		}
+		return value
+	},
+}
+
+// RenderTemplate renders a post body with text/template and TemplateFuncs. name is
+// used in error messages. Shortcodes and include directives, such as
+// {{< include "main.go" >}}, are not template actions and are kept as they are.
+func RenderTemplate(name, body string, data any) (string, error) {
+	shortcodes := []string{}
+	mask := func(match string) string {
+		shortcodes = append(shortcodes, match)
+		return "\x00" + strconv.Itoa(len(shortcodes)-1) + "\x00"
+	}
+	body = shortcodeCommentRe.ReplaceAllStringFunc(body, mask)
+	body = shortcodeRe.ReplaceAllStringFunc(body, mask)
+
+	tmpl, err := template.New(name).Funcs(TemplateFuncs).Parse(body)
+	if err != nil {
+		return "", fmt.Errorf("could not parse template: %w", err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("could not render template: %w", err)
+	}
+	return maskedShortcodeRe.ReplaceAllStringFunc(b.String(), func(match string) string {
+		i, _ := strconv.Atoi(strings.Trim(match, "\x00"))
+		if i >= len(shortcodes) {
+			return match
+		}
+		return shortcodes[i]
+	}), nil
+}
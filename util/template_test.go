@@ -0,0 +1,32 @@
+package util
+
+import "testing"
+
+func TestRenderTemplate(t *testing.T) {
+	data := map[string]any{"Version": "1.2.0"}
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr bool
+	}{
+		{"action", "Version {{ .Version }}", "Version 1.2.0", false},
+		{"function", `{{ "Hello World" | lower }}`, "hello world", false},
+		{"default", `{{ .Missing | default "none" }}`, "none", false},
+		{"shortcode", `{{< include "main.go" >}} {{ .Version }}`, `{{< include "main.go" >}} 1.2.0`, false},
+		{"percent shortcode", `{{% notice info %}}text{{% /notice %}}`, `{{% notice info %}}text{{% /notice %}}`, false},
+		{"escaped shortcode", `{{</* youtube abc */>}}`, `{{</* youtube abc */>}}`, false},
+		{"invalid", "{{ .Version ", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderTemplate("post.md", tt.body, data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RenderTemplate(%q) error = %v, wantErr %v", tt.body, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("RenderTemplate(%q) = %q, want %q", tt.body, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+// DefaultTOCDepth is the number of heading levels in a table of contents if
+// neither `toc_depth` nor `post.toc.depth` is set.
+const DefaultTOCDepth = 3
+
+// tocMarkerRe matches a line marking where the table of contents goes.
+var tocMarkerRe = regexp.MustCompile(`(?mi)^[ \t]*(?:\[\[?TOC\]\]?|<!--\s*toc\s*-->)[ \t]*\n?`)
+
+// HeadingSlug returns the anchor of a heading the way GitHub builds it: lowercase,
+// without punctuation and with spaces replaced by hyphens. CJK and other letters are kept.
+func HeadingSlug(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
+
+type tocEdit struct {
+	offset int
+	remove int
+	insert string
+}
+
+// InsertTOC inserts a table of contents of the headings of the body, up to depth levels
+// below the highest one, at a [TOC] marker or else after the first paragraph. If anchors
+// is set, every heading gets an <a id> anchor, so the links work whatever the renderer.
+func InsertTOC(body string, depth int, anchors bool) string {
+	if depth <= 0 {
+		depth = DefaultTOCDepth
+	}
+	doc := &Document{Body: []byte(body)}
+
+	type heading struct {
+		level int
+		text  string
+		slug  string
+		end   int
+	}
+	headings := []heading{}
+	seen := map[string]int{}
+	insertAt := -1
+	for n := doc.AST().FirstChild(); n != nil; n = n.NextSibling() {
+		switch node := n.(type) {
+		case *ast.Heading:
+			text := strings.TrimSpace(nodeText(node, doc.Body))
+			if text == "" || node.Lines().Len() == 0 {
+				continue
+			}
+			slug := HeadingSlug(text)
+			if count, ok := seen[slug]; ok {
+				seen[slug] = count + 1
+				slug = fmt.Sprintf("%s-%d", slug, count+1)
+			} else {
+				seen[slug] = 0
+			}
+			lines := node.Lines()
+			headings = append(headings, heading{node.Level, text, slug, lines.At(lines.Len() - 1).Stop})
+		case *ast.Paragraph:
+			if insertAt < 0 && node.Lines().Len() > 0 {
+				lines := node.Lines()
+				insertAt = lines.At(lines.Len() - 1).Stop
+			}
+		}
+	}
+	if len(headings) == 0 {
+		return body
+	}
+
+	top := headings[0].level
+	for _, h := range headings {
+		top = min(top, h.level)
+	}
+	var toc strings.Builder
+	for _, h := range headings {
+		if h.level-top >= depth {
+			continue
+		}
+		text := strings.NewReplacer("[", `\[`, "]", `\]`).Replace(h.text)
+		fmt.Fprintf(&toc, "%s- [%s](#%s)\n", strings.Repeat("  ", h.level-top), text, h.slug)
+	}
+
+	edits := []tocEdit{}
+	if anchors {
+		for _, h := range headings {
+			edits = append(edits, tocEdit{offset: h.end, insert: fmt.Sprintf(` <a id="%s"></a>`, h.slug)})
+		}
+	}
+	ranges := codeRanges(body)
+	marker := []int(nil)
+	for _, loc := range tocMarkerRe.FindAllStringIndex(body, -1) {
+		if !inRanges(ranges, loc[0]) {
+			marker = loc
+			break
+		}
+	}
+	switch {
+	case marker != nil:
+		edits = append(edits, tocEdit{offset: marker[0], remove: marker[1] - marker[0], insert: toc.String()})
+	case insertAt >= 0:
+		edits = append(edits, tocEdit{offset: insertAt, insert: "\n\n" + strings.TrimRight(toc.String(), "\n")})
+	default:
+		edits = append(edits, tocEdit{offset: 0, insert: toc.String() + "\n"})
+	}
+
+	// apply from the end, so the offsets of earlier edits stay valid
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].offset > edits[j].offset })
+	for _, edit := range edits {
+		body = body[:edit.offset] + edit.insert + body[edit.offset+edit.remove:]
+	}
+	return body
+}
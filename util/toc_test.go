@@ -0,0 +1,72 @@
+package util
+
+import "testing"
+
+func TestHeadingSlug(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Hello World", "hello-world"},
+		{"中文 标题", "中文-标题"},
+		{"What's new?", "whats-new"},
+		{"A  B", "a--b"},
+	}
+	for _, tt := range tests {
+		if got := HeadingSlug(tt.text); got != tt.want {
+			t.Errorf("HeadingSlug(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestInsertTOC(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		depth   int
+		anchors bool
+		want    string
+	}{
+		{
+			name: "after first paragraph with default depth",
+			body: "Intro text.\n\n# One\n\n## Two\n\n### Three\n\n#### Four\n",
+			want: "Intro text.\n\n- [One](#one)\n  - [Two](#two)\n    - [Three](#three)\n\n# One\n\n## Two\n\n### Three\n\n#### Four\n",
+		},
+		{
+			name:  "marker and duplicate headings",
+			body:  "[TOC]\n\n# A\n\n# A\n",
+			depth: 2,
+			want:  "- [A](#a)\n- [A](#a-1)\n\n# A\n\n# A\n",
+		},
+		{
+			name:    "anchors and depth",
+			body:    "# A\n\n## B\n",
+			depth:   1,
+			anchors: true,
+			want:    "- [A](#a)\n\n# A <a id=\"a\"></a>\n\n## B <a id=\"b\"></a>\n",
+		},
+		{
+			name: "marker in code block",
+			body: "```\n[TOC]\n```\n\nText.\n\n# H\n",
+			want: "```\n[TOC]\n```\n\nText.\n\n- [H](#h)\n\n# H\n",
+		},
+		{
+			name: "comment marker and brackets",
+			body: "<!-- toc -->\n## [x] y\n",
+			want: "- [\\[x\\] y](#x-y)\n## [x] y\n",
+		},
+		{
+			name:    "no headings",
+			body:    "No headings.\n",
+			anchors: true,
+			want:    "No headings.\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InsertTOC(tt.body, tt.depth, tt.anchors); got != tt.want {
+				t.Errorf("InsertTOC(%q) = %q, want %q", tt.body, got, tt.want)
+			}
+		})
+	}
+}